@@ -0,0 +1,91 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// partialSeriesRE matches a partial release series, "vX", as accepted by
+// ResolveLatest.
+var partialSeriesRE = regexp.MustCompile(`^v([1-9][0-9]*)$`)
+
+// ResolveLatest returns the newest version in catalog matching target, which
+// may take one of the following shapes:
+//
+// - "latest": matches every version in the catalog
+// - "vX.Y" (a release series, eg "v24.1"): matches versions in that series
+// - "vX" (a partial series, eg "v24"): matches versions in any series of year X
+//
+// Every catalog entry is a candidate, including prereleases, cloudonly, and
+// adhoc builds; callers that only want GA releases should filter the catalog
+// first. The catalog does not need to be sorted. ResolveLatest returns false if
+// target has none of the shapes above, or if no catalog entry matches it.
+func ResolveLatest(target string, catalog []Version) (Version, bool) {
+	var match func(Version) bool
+	if target == "latest" {
+		match = func(Version) bool { return true }
+	} else if series, err := ParseMajorVersion(target); err == nil {
+		match = func(v Version) bool { return v.Major().Equals(series) }
+	} else if groups := partialSeriesRE.FindStringSubmatch(target); groups != nil {
+		year, _ := strconv.Atoi(groups[1])
+		match = func(v Version) bool { return v.year == year }
+	} else {
+		return Version{}, false
+	}
+
+	var latest Version
+	found := false
+	for _, v := range catalog {
+		if match(v) && (!found || v.Compare(latest) > 0) {
+			latest = v
+			found = true
+		}
+	}
+	return latest, found
+}
+
+// NextInCatalog returns the smallest version in catalog that is strictly
+// greater than v, eg the next release to upgrade to. The catalog must be
+// sorted in ascending order (see [Version.Compare]); it's searched with a
+// binary search. v doesn't need to be in the catalog: if it falls between two
+// entries, the greater of them is returned. NextInCatalog returns false if no
+// entry is greater than v.
+func (v Version) NextInCatalog(catalog []Version) (Version, bool) {
+	// i is the index of the first entry greater than v
+	i, found := BinarySearch(catalog, v)
+	for found && i < len(catalog) && catalog[i].Compare(v) == 0 {
+		i++
+	}
+	if i == len(catalog) {
+		return Version{}, false
+	}
+	return catalog[i], true
+}
+
+// PrevInCatalog returns the greatest version in catalog that is strictly less
+// than v. Like [Version.NextInCatalog], the catalog must be sorted in ascending
+// order, and v doesn't need to be in it: if it falls between two entries, the
+// lesser of them is returned. PrevInCatalog returns false if no entry is less
+// than v.
+func (v Version) PrevInCatalog(catalog []Version) (Version, bool) {
+	// i is the index of the first entry at least v
+	i, _ := BinarySearch(catalog, v)
+	if i == 0 {
+		return Version{}, false
+	}
+	return catalog[i-1], true
+}
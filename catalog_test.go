@@ -0,0 +1,131 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCatalog returns a small, unsorted catalog of versions spanning a few
+// release series.
+func testCatalog() []Version {
+	var catalog []Version
+	for _, str := range shuffleStrings([]string{
+		"v23.1.14",
+		"v23.2.0-rc.1",
+		"v23.2.0",
+		"v23.2.5",
+		"v24.1.0-beta.2",
+		"v24.1.0",
+		"v24.1.3",
+		"v24.1.10",
+		"v24.2.0-alpha.1",
+	}) {
+		catalog = append(catalog, MustParse(str))
+	}
+	return catalog
+}
+
+func TestResolveLatest(t *testing.T) {
+	testCases := []struct {
+		target string
+		want   string
+	}{
+		{"latest", "v24.2.0-alpha.1"},
+		{"v24.1", "v24.1.10"},
+		{"v23.2", "v23.2.5"},
+		{"v23", "v23.2.5"},
+		{"v24", "v24.2.0-alpha.1"},
+
+		// no matches
+		{"v22.2", ""},
+		{"v25", ""},
+
+		// not a valid target
+		{"24.1", ""},
+		{"v24.1.3", ""},
+		{"newest", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.target, func(t *testing.T) {
+			got, ok := ResolveLatest(tc.target, testCatalog())
+			if tc.want == "" {
+				require.False(t, ok)
+				require.True(t, got.Empty())
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, MustParse(tc.want), got)
+		})
+	}
+}
+
+func TestNextPrevInCatalog(t *testing.T) {
+	catalog := testCatalog()
+	slices.SortFunc(catalog, Version.Compare)
+
+	testCases := []struct {
+		v    string
+		next string
+		prev string
+	}{
+		// in the catalog
+		{"v23.2.0", "v23.2.5", "v23.2.0-rc.1"},
+		{"v24.1.3", "v24.1.10", "v24.1.0"},
+		{"v23.1.14", "v23.2.0-rc.1", ""},
+		{"v24.2.0-alpha.1", "", "v24.1.10"},
+
+		// between entries
+		{"v23.2.1", "v23.2.5", "v23.2.0"},
+		{"v24.1.0-rc.1", "v24.1.0", "v24.1.0-beta.2"},
+		{"v24.1.4", "v24.1.10", "v24.1.3"},
+
+		// outside the catalog
+		{"v22.2.0", "v23.1.14", ""},
+		{"v25.1.0", "", "v24.2.0-alpha.1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v, func(t *testing.T) {
+			v := MustParse(tc.v)
+
+			next, ok := v.NextInCatalog(catalog)
+			require.Equal(t, tc.next != "", ok)
+			if ok {
+				require.Equal(t, tc.next, next.String())
+			}
+
+			prev, ok := v.PrevInCatalog(catalog)
+			require.Equal(t, tc.prev != "", ok)
+			if ok {
+				require.Equal(t, tc.prev, prev.String())
+			}
+		})
+	}
+
+	// duplicate entries are skipped over
+	dups := mustParseAll("v24.1.0", "v24.1.1", "v24.1.1", "v24.1.2")
+	next, ok := MustParse("v24.1.1").NextInCatalog(dups)
+	require.True(t, ok)
+	require.Equal(t, "v24.1.2", next.String())
+	prev, ok := MustParse("v24.1.1").PrevInCatalog(dups)
+	require.True(t, ok)
+	require.Equal(t, "v24.1.0", prev.String())
+
+	_, ok = MustParse("v24.1.1").NextInCatalog(nil)
+	require.False(t, ok)
+}
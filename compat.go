@@ -0,0 +1,116 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import "github.com/cockroachdb/errors"
+
+// Classifications returned by [ClassifyPair].
+const (
+	// Compatible means the binary can run in the cluster as-is.
+	Compatible = "Compatible"
+	// UpgradeRequired means the binary can join the cluster, but the cluster
+	// is running a mixed-version upgrade that has yet to be finalized.
+	UpgradeRequired = "UpgradeRequired"
+	// Unsupported means the binary cannot run in the cluster.
+	Unsupported = "Unsupported"
+)
+
+// ClassifyPair classifies a (binary version, cluster version) pair according to
+// CockroachDB's mixed-version rules. Only the release series of each version
+// is considered:
+//
+// - If the binary and cluster are in the same series, they're Compatible.
+// - If the binary is in the series immediately following the cluster's, the
+// pair is UpgradeRequired: the binary can join the cluster, and the cluster
+// version can be finalized to the binary's series.
+// - Anything else is Unsupported: binaries can't run a cluster with a newer
+// series, nor can they skip a series.
+//
+// Series are stepped using ordinalsPerYear series per year; if it is zero,
+// CockroachDB's published cadence is used (see [MajorVersion.Successor]). Empty
+// versions are always Unsupported.
+func ClassifyPair(binary, cluster Version, ordinalsPerYear int) string {
+	if binary.Empty() || cluster.Empty() {
+		return Unsupported
+	}
+	switch binary.Major() {
+	case cluster.Major():
+		return Compatible
+	case cluster.Major().Successor(ordinalsPerYear):
+		return UpgradeRequired
+	default:
+		return Unsupported
+	}
+}
+
+// WireCompatible determines whether binaries at versions v and w can run
+// together in a mixed-version cluster: their release series must be the same
+// or adjacent, and neither may be a build that breaks wire compatibility (see
+// [Version.BreaksWireCompat]). Series are stepped using ordinalsPerYear series
+// per year; if it is zero, CockroachDB's published cadence is used (see
+// [MajorVersion.Successor]), so eg v24.3 and v25.1 are adjacent.
+func (v Version) WireCompatible(w Version, ordinalsPerYear int) bool {
+	if v.Empty() || w.Empty() || v.BreaksWireCompat() || w.BreaksWireCompat() {
+		return false
+	}
+	vs, ws := v.Major(), w.Major()
+	return vs.Equals(ws) || vs.Successor(ordinalsPerYear).Equals(ws) || ws.Successor(ordinalsPerYear).Equals(vs)
+}
+
+// An UpgradePolicy determines which upgrades [Version.CanUpgradeTo] allows.
+type UpgradePolicy int
+
+const (
+	// UpgradeToSameOrNextSeries allows upgrades within a release series, and to
+	// the series immediately following it (see [MajorVersion.Successor]), eg
+	// v24.1.3 to v24.1.5 or v24.2.0, but not v24.3.0.
+	UpgradeToSameOrNextSeries = UpgradePolicy(iota + 1)
+	// UpgradeToAnyLater allows upgrades to any later version.
+	UpgradeToAnyLater
+)
+
+// CanUpgradeTo returns nil if upgrading from v to target is allowed by policy,
+// or an error explaining why it isn't. Series are stepped using
+// ordinalsPerYear series per year; if it is zero, CockroachDB's published
+// cadence is used (see [MajorVersion.Successor]), so eg v24.3 may be upgraded
+// to v25.1. Downgrades (to a target less than v, per
+// [Version.Compare]) are never allowed, nor are upgrades from or to the zero
+// Version, or the symbolic [Latest] version, which must be resolved (eg, with
+// [ResolveLatest]) first. Upgrading to a version equal to v is allowed.
+func (v Version) CanUpgradeTo(target Version, policy UpgradePolicy, ordinalsPerYear int) error {
+	if v.Empty() || target.Empty() {
+		return errors.New("cannot upgrade from or to an empty version")
+	}
+	if v.latest || target.latest {
+		return errors.New("cannot upgrade from or to the symbolic version latest")
+	}
+	if target.Compare(v) < 0 {
+		return errors.Newf("cannot downgrade from %s to %s", v.String(), target.String())
+	}
+	switch policy {
+	case UpgradeToSameOrNextSeries:
+		series := v.Major()
+		next := series.Successor(ordinalsPerYear)
+		if !target.Major().Equals(series) && !target.Major().Equals(next) {
+			return errors.Newf("cannot upgrade from %s to %s: upgrades must be within %s or to %s",
+				v.String(), target.String(), series.String(), next.String())
+		}
+		return nil
+	case UpgradeToAnyLater:
+		return nil
+	default:
+		return errors.Newf("unknown upgrade policy %d", policy)
+	}
+}
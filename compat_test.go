@@ -0,0 +1,155 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyPair(t *testing.T) {
+	testCases := []struct {
+		binary, cluster string
+		ordinalsPerYear int
+		want            string
+	}{
+		// same series
+		{"v24.1.0", "v24.1.0", 0, Compatible},
+		{"v24.1.5", "v24.1.0", 0, Compatible},
+		{"v24.1.0-rc.1", "v24.1.3", 0, Compatible},
+
+		// one series ahead
+		{"v24.2.0", "v24.1.3", 0, UpgradeRequired},
+		{"v24.1.0", "v23.2.0", 0, UpgradeRequired},
+		{"v25.1.0", "v24.3.1", 0, UpgradeRequired},
+		{"v26.1.0", "v25.4.1", 0, UpgradeRequired},
+		{"v25.1.0", "v24.3.1", 3, UpgradeRequired},
+		{"v23.1.0", "v22.2.9", 2, UpgradeRequired},
+
+		// binary older than the cluster
+		{"v24.1.9", "v24.2.0", 0, Unsupported},
+		{"v23.2.0", "v24.1.0", 0, Unsupported},
+
+		// skipping a series
+		{"v24.3.0", "v24.1.0", 0, Unsupported},
+		{"v25.1.0", "v24.3.1", 4, Unsupported},
+		{"v24.1.0", "v23.1.0", 2, Unsupported},
+		{"v24.1.0", "v23.1.0", 0, Unsupported},
+		{"v25.1.0", "v24.2.0", 0, Unsupported},
+
+		// empty versions
+		{"", "v24.1.0", 0, Unsupported},
+		{"v24.1.0", "", 0, Unsupported},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s on %s", tc.binary, tc.cluster), func(t *testing.T) {
+			var binary, cluster Version
+			if tc.binary != "" {
+				binary = MustParse(tc.binary)
+			}
+			if tc.cluster != "" {
+				cluster = MustParse(tc.cluster)
+			}
+			require.Equal(t, tc.want, ClassifyPair(binary, cluster, tc.ordinalsPerYear))
+		})
+	}
+	require.Panics(t, func() { ClassifyPair(Latest, MustParse("v24.1.0"), 0) })
+}
+
+func TestWireCompatible(t *testing.T) {
+	testCases := []struct {
+		a, b            string
+		ordinalsPerYear int
+		want            bool
+	}{
+		{"v24.1.0", "v24.1.3", 0, true},
+		{"v24.1.0", "v24.2.0-rc.1", 0, true},
+		{"v24.1.0", "v24.3.0", 0, false},
+
+		// across a year boundary
+		{"v24.1.0", "v23.2.0", 0, true},
+		{"v25.1.0", "v24.3.0", 0, true},
+		{"v25.4.1", "v26.1.0", 0, true},
+		{"v24.1.0", "v23.1.0", 0, false},
+		{"v25.1.0", "v24.3.0", 4, false},
+		{"v24.4.0", "v24.3.0", 4, true},
+		{"v23.1.0", "v22.2.0", 2, true},
+
+		// marked builds are never compatible
+		{"v24.1.0-incompat", "v24.1.0", 0, false},
+		{"v24.1.0", "v24.1.3-12-gabcdef1-incompat", 0, false},
+		{"v24.1.0-incompat", "v24.1.0-incompat", 0, false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s with %s/%d", tc.a, tc.b, tc.ordinalsPerYear), func(t *testing.T) {
+			a, b := MustParse(tc.a), MustParse(tc.b)
+			require.Equal(t, tc.want, a.WireCompatible(b, tc.ordinalsPerYear))
+			require.Equal(t, tc.want, b.WireCompatible(a, tc.ordinalsPerYear))
+		})
+	}
+	require.Panics(t, func() { MustParse("v24.1.0").WireCompatible(Latest, 0) })
+}
+
+func TestCanUpgradeTo(t *testing.T) {
+	testCases := []struct {
+		from, to   string
+		sameOrNext bool
+		anyLater   bool
+	}{
+		// within a series
+		{"v24.1.3", "v24.1.5", true, true},
+		{"v24.1.0-rc.1", "v24.1.0", true, true},
+		{"v24.1.3", "v24.1.3", true, true},
+		// to the next series, including across a year boundary
+		{"v24.1.3", "v24.2.0", true, true},
+		{"v24.3.3", "v25.1.0", true, true},
+		{"v23.2.3", "v24.1.0", true, true},
+		{"v25.4.3", "v26.1.0", true, true},
+		// skipping a series
+		{"v24.1.3", "v24.3.0", false, true},
+		{"v23.2.3", "v25.1.0", false, true},
+		// downgrades
+		{"v24.1.5", "v24.1.3", false, false},
+		{"v24.2.0", "v24.1.3", false, false},
+		{"v24.1.0", "v24.1.0-rc.1", false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.from+" to "+tc.to, func(t *testing.T) {
+			from, to := MustParse(tc.from), MustParse(tc.to)
+			require.Equal(t, tc.sameOrNext, from.CanUpgradeTo(to, UpgradeToSameOrNextSeries, 0) == nil)
+			require.Equal(t, tc.anyLater, from.CanUpgradeTo(to, UpgradeToAnyLater, 0) == nil)
+		})
+	}
+
+	err := MustParse("v24.1.5").CanUpgradeTo(MustParse("v24.1.3"), UpgradeToAnyLater, 0)
+	require.ErrorContains(t, err, "cannot downgrade from v24.1.5 to v24.1.3")
+	err = MustParse("v24.1.3").CanUpgradeTo(MustParse("v24.3.0"), UpgradeToSameOrNextSeries, 0)
+	require.ErrorContains(t, err, "upgrades must be within v24.1 or to v24.2")
+
+	// an explicit cadence
+	require.NoError(t, MustParse("v24.3.3").CanUpgradeTo(MustParse("v24.4.0"), UpgradeToSameOrNextSeries, 4))
+	err = MustParse("v24.3.3").CanUpgradeTo(MustParse("v25.1.0"), UpgradeToSameOrNextSeries, 4)
+	require.ErrorContains(t, err, "upgrades must be within v24.3 or to v24.4")
+	err = MustParse("v24.3.3").CanUpgradeTo(MustParse("v24.4.0"), UpgradeToSameOrNextSeries, 0)
+	require.ErrorContains(t, err, "upgrades must be within v24.3 or to v25.1")
+
+	require.Error(t, Version{}.CanUpgradeTo(MustParse("v24.1.3"), UpgradeToAnyLater, 0))
+	require.Error(t, MustParse("v24.1.3").CanUpgradeTo(Version{}, UpgradeToAnyLater, 0))
+	require.ErrorContains(t, MustParse("v24.1.3").CanUpgradeTo(Latest, UpgradeToAnyLater, 0), "latest")
+	require.ErrorContains(t, Latest.CanUpgradeTo(Latest, UpgradeToAnyLater, 0), "latest")
+	require.ErrorContains(t, MustParse("v24.1.3").CanUpgradeTo(MustParse("v24.1.4"), UpgradePolicy(0), 0), "unknown upgrade policy")
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// constraintOps are the supported comparison operators, mapped to a function
+// that reports whether a result from [Version.Compare] satisfies them. Longer
+// operators come first, so that prefix matching finds ">=" before ">".
+var constraintOps = []struct {
+	op    string
+	check func(cmp int) bool
+}{
+	{">=", func(cmp int) bool { return cmp >= 0 }},
+	{"<=", func(cmp int) bool { return cmp <= 0 }},
+	{"!=", func(cmp int) bool { return cmp != 0 }},
+	{">", func(cmp int) bool { return cmp > 0 }},
+	{"<", func(cmp int) bool { return cmp < 0 }},
+	{"=", func(cmp int) bool { return cmp == 0 }},
+}
+
+// lookupConstraintOp returns the check function for op, which must be exactly
+// one of the supported operators.
+func lookupConstraintOp(op string) (func(cmp int) bool, bool) {
+	for _, o := range constraintOps {
+		if o.op == op {
+			return o.check, true
+		}
+	}
+	return nil, false
+}
+
+// Satisfies returns true if v stands in relation op to other, where op is one
+// of =, !=, <, <=, >, >=; eg, v.Satisfies(">=", w) is v.AtLeast(w). Versions
+// are compared with [Version.Compare]. An error is returned if op is not a
+// supported operator.
+func (v Version) Satisfies(op string, other Version) (bool, error) {
+	check, ok := lookupConstraintOp(op)
+	if !ok {
+		return false, errors.Newf("invalid operator '%s': must be one of =, !=, <, <=, >, >=", op)
+	}
+	return check(v.Compare(other)), nil
+}
+
+// constraint is a single comparison against a version, like ">=v24.1.0".
+type constraint struct {
+	op      string
+	operand Version
+}
+
+func parseConstraint(str string) (constraint, error) {
+	for _, o := range constraintOps {
+		if operand, ok := strings.CutPrefix(str, o.op); ok {
+			v, err := Parse(strings.TrimSpace(operand))
+			if err != nil {
+				return constraint{}, errors.Wrapf(err, "invalid constraint '%s'", str)
+			}
+			return constraint{op: o.op, operand: v}, nil
+		}
+	}
+	return constraint{}, errors.Newf("invalid constraint '%s': must start with one of =, !=, <, <=, >, >=", str)
+}
+
+// A ConstraintSet is a set of version constraints, like
+// ">=v23.1.0,<v23.2.0 || >=v24.1.0". A ConstraintSet is made up of one or more
+// groups separated by "||", and is satisfied if any of its groups is. Each
+// group is a comma-separated list of constraints, and is satisfied if all of
+// its constraints are. Each constraint is an operator (one of =, !=, <, <=, >,
+// >=) followed by a version, and is checked using [Version.Compare], so the
+// usual prerelease ordering applies (eg, "v24.1.0-rc.1" satisfies "<v24.1.0").
+type ConstraintSet struct {
+	groups [][]constraint
+}
+
+// ParseConstraintSet creates a ConstraintSet from a string. Whitespace around
+// operators, versions, and separators is ignored.
+func ParseConstraintSet(str string) (ConstraintSet, error) {
+	var set ConstraintSet
+	for _, groupStr := range strings.Split(str, "||") {
+		var group []constraint
+		for _, constraintStr := range strings.Split(groupStr, ",") {
+			constraintStr = strings.TrimSpace(constraintStr)
+			if constraintStr == "" {
+				return ConstraintSet{}, errors.Newf("invalid constraint set '%s': empty constraint", str)
+			}
+			c, err := parseConstraint(constraintStr)
+			if err != nil {
+				return ConstraintSet{}, errors.Wrapf(err, "invalid constraint set '%s'", str)
+			}
+			group = append(group, c)
+		}
+		set.groups = append(set.groups, group)
+	}
+	return set, nil
+}
+
+// Check returns true if v satisfies the constraint set.
+func (c ConstraintSet) Check(v Version) bool {
+	for _, group := range c.groups {
+		if checkGroup(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGroup returns true if v satisfies every constraint in group.
+func checkGroup(group []constraint, v Version) bool {
+	for _, con := range group {
+		if ok, _ := v.Satisfies(con.op, con.operand); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a normalized form of the constraint set, with no whitespace
+// around operators or commas, and a single space around each "||".
+func (c ConstraintSet) String() string {
+	groups := make([]string, len(c.groups))
+	for i, group := range c.groups {
+		constraints := make([]string, len(group))
+		for j, con := range group {
+			constraints[j] = con.op + con.operand.String()
+		}
+		groups[i] = strings.Join(constraints, ",")
+	}
+	return strings.Join(groups, " || ")
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintSet(t *testing.T) {
+	testCases := []struct {
+		constraints string
+		normalized  string
+		matches     []string
+		nonMatches  []string
+	}{
+		{
+			constraints: ">=v23.1.0,<v23.2.0 || >=v24.1.0",
+			normalized:  ">=v23.1.0,<v23.2.0 || >=v24.1.0",
+			matches:     []string{"v23.1.0", "v23.1.28", "v23.2.0-rc.1", "v24.1.0", "v25.2.3", "v24.1.0-1-gabcdef1"},
+			nonMatches:  []string{"v22.2.9", "v23.2.0", "v24.1.0-rc.1"},
+		},
+		{
+			constraints: " > v24.1.0-rc.1 ,  <= v24.1.0 ",
+			normalized:  ">v24.1.0-rc.1,<=v24.1.0",
+			matches:     []string{"v24.1.0-rc.2", "v24.1.0-cloudonly.1", "v24.1.0"},
+			nonMatches:  []string{"v24.1.0-rc.1", "v24.1.0-beta.3", "v24.1.1"},
+		},
+		{
+			constraints: "=v24.1.0||=v24.2.0",
+			normalized:  "=v24.1.0 || =v24.2.0",
+			matches:     []string{"v24.1.0", "v24.2.0"},
+			nonMatches:  []string{"v24.1.1", "v24.3.0"},
+		},
+		{
+			constraints: "!=v24.1.0",
+			normalized:  "!=v24.1.0",
+			matches:     []string{"v24.1.1", "v24.1.0-rc.1"},
+			nonMatches:  []string{"v24.1.0"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.constraints, func(t *testing.T) {
+			c, err := ParseConstraintSet(tc.constraints)
+			require.NoError(t, err)
+			require.Equal(t, tc.normalized, c.String())
+
+			for _, v := range tc.matches {
+				require.Truef(t, c.Check(MustParse(v)), "expected %s to match", v)
+			}
+			for _, v := range tc.nonMatches {
+				require.Falsef(t, c.Check(MustParse(v)), "expected %s not to match", v)
+			}
+
+			// the normalized form is equivalent
+			reparsed, err := ParseConstraintSet(c.String())
+			require.NoError(t, err)
+			require.Equal(t, c.String(), reparsed.String())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, str := range []string{
+			"",
+			"v24.1.0",
+			">=v24.1.0,",
+			">=v24.1.0 ||",
+			">=v23.1.0 <v23.2.0",
+			">=v23.1.0 && <v23.2.0",
+			"=>v24.1.0",
+			">=<v24.1.0",
+			"~v24.1.0",
+			">=24.1",
+		} {
+			_, err := ParseConstraintSet(str)
+			require.Errorf(t, err, "expected error parsing '%s'", str)
+		}
+	})
+}
+
+func TestVersionSatisfies(t *testing.T) {
+	testCases := []struct {
+		v, op, other string
+		want         bool
+	}{
+		{"v24.1.0", "=", "v24.1.0", true},
+		{"v24.1.0", "=", "v24.1.1", false},
+		{"v24.1.0", "!=", "v24.1.1", true},
+		{"v24.1.0-rc.1", "<", "v24.1.0", true},
+		{"v24.1.0", "<", "v24.1.0", false},
+		{"v24.1.0", "<=", "v24.1.0", true},
+		{"v24.1.1", ">", "v24.1.0", true},
+		{"v24.1.0", ">=", "v24.1.0", true},
+		{"v24.1.0", ">=", "v24.1.1", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v+tc.op+tc.other, func(t *testing.T) {
+			ok, err := MustParse(tc.v).Satisfies(tc.op, MustParse(tc.other))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, ok)
+		})
+	}
+
+	for _, op := range []string{"", "==", "=>", " >=", "~"} {
+		_, err := MustParse("v24.1.0").Satisfies(op, MustParse("v24.1.0"))
+		require.ErrorContains(t, err, "invalid operator")
+	}
+}
@@ -20,6 +20,43 @@ func TestVersionJSONSerialization(t *testing.T) {
 	require.Equal(t, v, parsed)
 }
 
+func TestLatestJSON(t *testing.T) {
+	// latest must be resolved before it's marshaled, and isn't unmarshaled
+	_, err := json.Marshal(Latest)
+	require.ErrorContains(t, err, "latest")
+	_, err = Latest.MarshalJSONVerbose()
+	require.ErrorContains(t, err, "latest")
+	_, err = json.Marshal(NewNullVersion(Latest))
+	require.ErrorContains(t, err, "latest")
+	_, err = json.Marshal(StringVersion{Latest})
+	require.ErrorContains(t, err, "latest")
+
+	var parsed Version
+	require.Error(t, json.Unmarshal([]byte(`"latest"`), &parsed))
+	require.Error(t, json.Unmarshal([]byte(`{"$raw":"latest"}`), &parsed))
+}
+
+func TestVersionJSONBareString(t *testing.T) {
+	var parsed Version
+	err := json.Unmarshal([]byte(`"v24.1.0-rc.1"`), &parsed)
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.0-rc.1"), parsed)
+
+	type document struct {
+		Version Version
+	}
+	var doc document
+	err = json.Unmarshal([]byte(`{"Version": "v23.2.4"}`), &doc)
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v23.2.4"), doc.Version)
+
+	err = json.Unmarshal([]byte(`"not a version"`), &parsed)
+	require.ErrorContains(t, err, "invalid version string")
+
+	err = json.Unmarshal([]byte(`""`), &parsed)
+	require.Error(t, err)
+}
+
 func TestNullVersionJSONSerialization(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		v := MustParse("v20.1.2-alpha.3-cloudonly.4")
@@ -49,4 +86,187 @@ func TestNullVersionJSONSerialization(t *testing.T) {
 		require.False(t, parsed.Valid)
 		require.Equal(t, Version{}, parsed.Version)
 	})
+
+	t.Run("invalid with version", func(t *testing.T) {
+		blob, err := json.Marshal(NullVersion{Valid: false, Version: MustParse("v24.1.0")})
+		require.NoError(t, err)
+		require.Equal(t, `{"Valid":false,"Version":{"$raw":""}}`, string(blob))
+	})
+
+	t.Run("valid but empty", func(t *testing.T) {
+		nv := NullVersion{Valid: true}
+
+		blob, err := json.Marshal(nv)
+		require.NoError(t, err)
+		require.Equal(t, `{"Valid":true,"Version":{"$raw":""}}`, string(blob))
+
+		var parsed NullVersion
+		err = json.Unmarshal(blob, &parsed)
+		require.NoError(t, err)
+		require.Equal(t, nv, parsed)
+	})
+}
+
+func TestStringVersionJSON(t *testing.T) {
+	type response struct {
+		Version StringVersion `json:"version"`
+	}
+
+	blob, err := json.Marshal(response{StringVersion{MustParse("v24.1.0-rc.1")}})
+	require.NoError(t, err)
+	require.Equal(t, `{"version":"v24.1.0-rc.1"}`, string(blob))
+
+	var parsed response
+	require.NoError(t, json.Unmarshal(blob, &parsed))
+	require.Equal(t, MustParse("v24.1.0-rc.1"), parsed.Version.Version)
+	require.True(t, parsed.Version.AtLeast(MustParse("v24.1.0-beta.1")))
+
+	// the zero version round-trips as ""
+	blob, err = json.Marshal(response{})
+	require.NoError(t, err)
+	require.Equal(t, `{"version":""}`, string(blob))
+	require.NoError(t, json.Unmarshal(blob, &parsed))
+	require.True(t, parsed.Version.Empty())
+
+	err = json.Unmarshal([]byte(`{"version":"v24"}`), &parsed)
+	require.ErrorContains(t, err, "invalid version string")
+	err = json.Unmarshal([]byte(`{"version":{"$raw":"v24.1.0"}}`), &parsed)
+	require.Error(t, err)
+
+	// Version itself still uses the envelope
+	blob, err = json.Marshal(MustParse("v24.1.0"))
+	require.NoError(t, err)
+	require.Equal(t, `{"$raw":"v24.1.0"}`, string(blob))
+}
+
+func TestMajorVersionJSON(t *testing.T) {
+	type config struct {
+		Series MajorVersion `json:"series"`
+	}
+
+	blob, err := json.Marshal(config{MustParseMajorVersion("v24.1")})
+	require.NoError(t, err)
+	require.Equal(t, `{"series":"v24.1"}`, string(blob))
+
+	var parsed config
+	require.NoError(t, json.Unmarshal(blob, &parsed))
+	require.Equal(t, MustParseMajorVersion("v24.1"), parsed.Series)
+
+	// the zero value round-trips as null
+	blob, err = json.Marshal(config{})
+	require.NoError(t, err)
+	require.Equal(t, `{"series":null}`, string(blob))
+	parsed = config{MustParseMajorVersion("v24.1")}
+	require.NoError(t, json.Unmarshal(blob, &parsed))
+	require.True(t, parsed.Series.Empty())
+
+	parsed = config{MustParseMajorVersion("v24.1")}
+	require.NoError(t, json.Unmarshal([]byte(`{"series":""}`), &parsed))
+	require.True(t, parsed.Series.Empty())
+
+	err = json.Unmarshal([]byte(`{"series":"v24.1.0"}`), &parsed)
+	require.ErrorContains(t, err, "not a valid CockroachDB major version")
+
+	// the legacy object form, written by the default struct encoding
+	parsed = config{}
+	require.NoError(t, json.Unmarshal([]byte(`{"series":{"Year":24,"Ordinal":1}}`), &parsed))
+	require.Equal(t, MustParseMajorVersion("v24.1"), parsed.Series)
+	parsed = config{MustParseMajorVersion("v24.1")}
+	require.NoError(t, json.Unmarshal([]byte(`{"series":{"Year":0,"Ordinal":0}}`), &parsed))
+	require.True(t, parsed.Series.Empty())
+	err = json.Unmarshal([]byte(`{"series":{"Year":24,"Ordinal":0}}`), &parsed)
+	require.ErrorContains(t, err, "not a valid CockroachDB major version")
+	err = json.Unmarshal([]byte(`{"series":{"Year":"24"}}`), &parsed)
+	require.Error(t, err)
+	err = json.Unmarshal([]byte(`{"series":24.1}`), &parsed)
+	require.Error(t, err)
+}
+
+func TestMajorVersionText(t *testing.T) {
+	text, err := MustParseMajorVersion("v23.2").MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "v23.2", string(text))
+
+	var m MajorVersion
+	require.NoError(t, m.UnmarshalText(text))
+	require.Equal(t, MustParseMajorVersion("v23.2"), m)
+
+	text, err = MajorVersion{}.MarshalText()
+	require.NoError(t, err)
+	require.Empty(t, text)
+	require.NoError(t, m.UnmarshalText(text))
+	require.True(t, m.Empty())
+
+	require.Error(t, m.UnmarshalText([]byte("23.2")))
+
+	// MarshalText makes MajorVersion usable as a JSON map key
+	blob, err := json.Marshal(map[MajorVersion]int{MustParseMajorVersion("v24.1"): 3})
+	require.NoError(t, err)
+	require.Equal(t, `{"v24.1":3}`, string(blob))
+}
+
+func TestVersionJSONVerbose(t *testing.T) {
+	blob, err := MustParse("v24.1.0-rc.1").MarshalJSONVerbose()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"raw":"v24.1.0-rc.1","year":24,"ordinal":1,"patch":0,"phase":"rc","phaseOrdinal":1}`, string(blob))
+
+	blob, err = MustParse("v22.2.10-1-g7b8322d67c-fips").MarshalJSONVerbose()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"raw":"v22.2.10-1-g7b8322d67c-fips","year":22,"ordinal":2,"patch":10,"customOrdinal":1,"fips":true}`, string(blob))
+
+	blob, err = MustParse("v24.3.0-alpha.1-cloudonly.2-incompat+gpu").MarshalJSONVerbose()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"raw":"v24.3.0-alpha.1-cloudonly.2-incompat+gpu","year":24,"ordinal":3,"patch":0,"phase":"alpha","phaseOrdinal":1,"phaseSubOrdinal":2,"incompat":true,"buildMetadata":"gpu"}`, string(blob))
+
+	// the verbose form round-trips, using raw
+	for _, str := range []string{"v24.1.0-rc.1", "v23.1.0-swenson-mr-4", "v24.1.3"} {
+		v := MustParse(str)
+		blob, err := v.MarshalJSONVerbose()
+		require.NoError(t, err)
+		var parsed Version
+		require.NoError(t, json.Unmarshal(blob, &parsed))
+		require.Equal(t, v, parsed)
+	}
+
+	// raw is authoritative
+	var parsed Version
+	require.NoError(t, json.Unmarshal([]byte(`{"raw":"v24.1.3","year":99,"phase":"beta"}`), &parsed))
+	require.Equal(t, MustParse("v24.1.3"), parsed)
+
+	require.Error(t, json.Unmarshal([]byte(`{"raw":24}`), &parsed))
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"year":24}`), &parsed), "missing $raw key")
+}
+
+func TestPhaseAndGranularityJSON(t *testing.T) {
+	type config struct {
+		MinPhase    Phase       `json:"minPhase"`
+		Granularity Granularity `json:"granularity"`
+	}
+
+	for _, p := range Phases(true) {
+		blob, err := json.Marshal(config{MinPhase: p, Granularity: Patch})
+		require.NoError(t, err)
+		require.Equal(t, `{"minPhase":"`+p.String()+`","granularity":"patch"}`, string(blob))
+
+		var parsed config
+		require.NoError(t, json.Unmarshal(blob, &parsed))
+		require.Equal(t, config{MinPhase: p, Granularity: Patch}, parsed)
+	}
+
+	var parsed config
+	require.NoError(t, json.Unmarshal([]byte(`{"minPhase":"rc","granularity":"major"}`), &parsed))
+	require.Equal(t, config{MinPhase: RC, Granularity: Major}, parsed)
+
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"minPhase":"gamma"}`), &parsed), "unknown phase 'gamma'")
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"minPhase":"RC"}`), &parsed), "unknown phase 'RC'")
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"granularity":"minor"}`), &parsed), "unknown granularity 'minor'")
+
+	// the zero values aren't valid phases or granularities
+	_, err := json.Marshal(config{})
+	require.ErrorContains(t, err, "invalid phase 0")
+	_, err = json.Marshal(config{MinPhase: RC})
+	require.ErrorContains(t, err, "invalid granularity 0")
+
+	require.Equal(t, "major", Major.String())
+	require.Equal(t, "Granularity(0)", Granularity(0).String())
 }
@@ -0,0 +1,47 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import "database/sql/driver"
+
+// LenientVersion is a Version that can be scanned from the database without
+// failing: values that Version.Scan rejects (unparseable strings, NULL, or
+// non-string values) are scanned as the zero Version, and the error is
+// recorded in ScanErr rather than returned. This is useful for bulk imports
+// from messy tables, where one bad row shouldn't abort the whole batch.
+//
+// Beware that this silently loses data: a bad value scans as the zero Version,
+// which is indistinguishable from an empty version unless ScanErr is checked.
+type LenientVersion struct {
+	Version Version
+	// ScanErr holds the error from the most recent Scan, if any.
+	ScanErr error
+}
+
+// Value implements [database/sql/driver.Valuer].
+func (l LenientVersion) Value() (driver.Value, error) {
+	return l.Version.Value()
+}
+
+// Scan implements [database/sql.Scanner]. It never returns an error.
+func (l *LenientVersion) Scan(value interface{}) error {
+	var v Version
+	if err := v.Scan(value); err != nil {
+		*l = LenientVersion{ScanErr: err}
+		return nil
+	}
+	*l = LenientVersion{Version: v}
+	return nil
+}
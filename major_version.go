@@ -16,6 +16,9 @@ package version
 
 import (
 	"cmp"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"strconv"
 
@@ -30,9 +33,12 @@ type MajorVersion struct {
 	Year, Ordinal int
 }
 
-// ParseMajorVersion constructs a MajorVersion from a string.
+// ParseMajorVersion constructs a MajorVersion from a series string, eg
+// "v25.1". Full versions such as "v25.1.2" are rejected; use
+// [MajorVersionFrom] to accept either form. As with [Parse], the year must be
+// positive, so "v0.1" is rejected.
 func ParseMajorVersion(versionStr string) (MajorVersion, error) {
-	majorVersionRE := regexp.MustCompile(`^v(0|[1-9][0-9]*)\.([1-9][0-9]*)$`)
+	majorVersionRE := regexp.MustCompile(`^v([1-9][0-9]*)\.([1-9][0-9]*)$`)
 	if !majorVersionRE.MatchString(versionStr) {
 		return MajorVersion{}, errors.Newf("not a valid CockroachDB major version: %s", versionStr)
 	}
@@ -42,6 +48,20 @@ func ParseMajorVersion(versionStr string) (MajorVersion, error) {
 	return MajorVersion{year, ordinal}, nil
 }
 
+// MajorVersionFrom constructs a MajorVersion from either a series string, eg
+// "v25.1", or a full version string, eg "v25.1.2-rc.1", which is truncated to
+// its series. Prefer [ParseMajorVersion] when only a series is valid input.
+func MajorVersionFrom(str string) (MajorVersion, error) {
+	if m, err := ParseMajorVersion(str); err == nil {
+		return m, nil
+	}
+	v, err := Parse(str)
+	if err != nil {
+		return MajorVersion{}, errors.Newf("not a valid CockroachDB major or full version: %s", str)
+	}
+	return v.Major(), nil
+}
+
 // MustParseMajorVersion is like ParseMajorVersion but panics on any error.
 // Recommended as an initializer for global values.
 func MustParseMajorVersion(versionStr string) MajorVersion {
@@ -72,6 +92,22 @@ func (m MajorVersion) AtLeast(o MajorVersion) bool {
 	return m.Compare(o) >= 0
 }
 
+// Max returns the greater of m and o.
+func (m MajorVersion) Max(o MajorVersion) MajorVersion {
+	if o.Compare(m) > 0 {
+		return o
+	}
+	return m
+}
+
+// Min returns the lesser of m and o.
+func (m MajorVersion) Min(o MajorVersion) MajorVersion {
+	if o.Compare(m) < 0 {
+		return o
+	}
+	return m
+}
+
 // Empty returns true if the MajorVersion is the zero value.
 func (m MajorVersion) Empty() bool {
 	return m.Compare(MajorVersion{}) == 0
@@ -86,3 +122,229 @@ func (m MajorVersion) String() string {
 func (m MajorVersion) SafeFormat(p redact.SafePrinter, _ rune) {
 	p.Printf("v%d.%d", m.Year, m.Ordinal)
 }
+
+// Value implements [database/sql/driver.Valuer], writing the "vX.Y" form. As
+// with [Version.Value], the zero MajorVersion is written as an empty string;
+// use [NullMajorVersion] for a nullable column.
+func (m MajorVersion) Value() (driver.Value, error) {
+	if m.Empty() {
+		return "", nil
+	}
+	return m.String(), nil
+}
+
+// Scan implements [database/sql.Scanner]. As with [Version.Scan], an empty
+// string is read as the zero MajorVersion, and NULL is an error.
+func (m *MajorVersion) Scan(value interface{}) error {
+	if value == nil {
+		return errors.New("non-nil MajorVersion string required")
+	}
+	if str, ok := value.(string); ok {
+		if str == "" {
+			*m = MajorVersion{}
+			return nil
+		}
+		parsed, err := ParseMajorVersion(str)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	return errors.Newf("cannot convert %T to MajorVersion", value)
+}
+
+// MarshalText implements [encoding.TextMarshaler], writing the "vX.Y" form.
+// The zero MajorVersion is written as empty text.
+func (m MajorVersion) MarshalText() ([]byte, error) {
+	if m.Empty() {
+		return []byte{}, nil
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. Empty text is read as
+// the zero MajorVersion.
+func (m *MajorVersion) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = MajorVersion{}
+		return nil
+	}
+	parsed, err := ParseMajorVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalJSON implements [encoding/json.Marshaler], writing the "vX.Y" form
+// as a JSON string. The zero MajorVersion is written as null.
+func (m MajorVersion) MarshalJSON() ([]byte, error) {
+	if m.Empty() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler]. Both null and "" are
+// read as the zero MajorVersion. In addition to the "vX.Y" string written by
+// MarshalJSON, UnmarshalJSON accepts the {"Year": X, "Ordinal": Y} object
+// written by the default struct encoding, which is how series were stored
+// before MarshalJSON existed.
+func (m *MajorVersion) UnmarshalJSON(data []byte) error {
+	var str *string
+	if err := json.Unmarshal(data, &str); err != nil {
+		// the legacy object form; decode it without this method
+		type fields MajorVersion
+		var legacy fields
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return err
+		}
+		if MajorVersion(legacy).Empty() {
+			*m = MajorVersion{}
+			return nil
+		}
+		return m.UnmarshalText([]byte(MajorVersion(legacy).String()))
+	}
+	if str == nil {
+		*m = MajorVersion{}
+		return nil
+	}
+	return m.UnmarshalText([]byte(*str))
+}
+
+// firstCalendarYear is the year of CockroachDB's first calendar-versioned
+// release series, v19.1, which followed v2.1.
+const firstCalendarYear = 19
+
+// Successor returns the release series that follows m, rolling over to the
+// first series of the next year after ordinalsPerYear series.
+//
+// If ordinalsPerYear is zero, CockroachDB's published release series are
+// followed instead: v1.1, v2.1, then v19.1 (when calendar versioning began),
+// then two series a year through v23, three in v24 (v24.1 through v24.3), and
+// four from v25 on. For example, the successor of v2.1 is v19.1, and the
+// successor of v24.3 is v25.1. The v1.0 and v2.0 series aren't included,
+// since a series' ordinal must be at least 1.
+func (m MajorVersion) Successor(ordinalsPerYear int) MajorVersion {
+	nextYear := m.Year + 1
+	if ordinalsPerYear == 0 {
+		ordinalsPerYear = seriesInYear(m.Year)
+		if m.Year < firstCalendarYear && nextYear > 2 {
+			nextYear = firstCalendarYear
+		}
+	}
+	if m.Ordinal >= ordinalsPerYear {
+		return MajorVersion{Year: nextYear, Ordinal: 1}
+	}
+	return MajorVersion{Year: m.Year, Ordinal: m.Ordinal + 1}
+}
+
+// Predecessor returns the release series that precedes m, rolling back to
+// the last series of the previous year when m is the first series of its
+// year. As with [MajorVersion.Successor], the previous year has
+// ordinalsPerYear series, or if it's zero, CockroachDB's published release
+// series are followed; eg, the predecessor of v24.1 is v23.2, and the
+// predecessor of v19.1 is v2.1. The predecessor of the very first series,
+// v1.1, is the zero MajorVersion, which can be detected with
+// [MajorVersion.Empty].
+func (m MajorVersion) Predecessor(ordinalsPerYear int) MajorVersion {
+	if m.Ordinal > 1 {
+		return MajorVersion{Year: m.Year, Ordinal: m.Ordinal - 1}
+	}
+	if m.Year <= 1 {
+		return MajorVersion{}
+	}
+	prevYear := m.Year - 1
+	if ordinalsPerYear == 0 {
+		if m.Year <= firstCalendarYear && prevYear > 2 {
+			prevYear = 2
+		}
+		ordinalsPerYear = seriesInYear(prevYear)
+	}
+	return MajorVersion{Year: prevYear, Ordinal: ordinalsPerYear}
+}
+
+// seriesInYear returns the number of release series (with an ordinal of at
+// least 1) CockroachDB published, or plans to publish, in the given year.
+func seriesInYear(year int) int {
+	switch {
+	case year < firstCalendarYear:
+		return 1
+	case year <= 23:
+		return 2
+	case year == 24:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// ScheduleKey returns a stable key identifying the release series in a
+// caller-provided release schedule, eg "2024H1" for v24.1. Keys follow the
+// two-releases-per-year convention: the year is 2000 plus m.Year, and the
+// ordinal maps to the half of that year (ordinal 1 is "H1", ordinal 2 is
+// "H2").
+//
+// Series outside that convention get keys that can't collide with it:
+//
+// - Ordinals past the second have no half of the year, so they're keyed by
+// release number instead; eg v24.3 is "2024R3".
+// - Series from before calendar versioning (see [MajorVersion.Successor]) are
+// keyed by the half of the year they were released in, which is 2016 plus
+// m.Year; the vX.0 series came out in the first half of the year, so eg v1.1
+// is "2017H2" and v2.1 is "2018H2".
+//
+// The zero MajorVersion has the empty key.
+func (m MajorVersion) ScheduleKey() string {
+	switch {
+	case m.Empty():
+		return ""
+	case m.Year < firstCalendarYear:
+		return fmt.Sprintf("%dH%d", 2016+m.Year, m.Ordinal+1)
+	case m.Ordinal > 2:
+		return fmt.Sprintf("%dR%d", 2000+m.Year, m.Ordinal)
+	default:
+		return fmt.Sprintf("%dH%d", 2000+m.Year, m.Ordinal)
+	}
+}
+
+// Contains returns true if v is in the release series m, ie if v's major
+// version is m. Pre-releases and builds of the series are included.
+func (m MajorVersion) Contains(v Version) bool {
+	return !v.Empty() && v.Major().Equals(m)
+}
+
+// FirstRelease returns the initial GA release of the series, "vX.Y.0".
+func (m MajorVersion) FirstRelease() Version {
+	return m.patch(0)
+}
+
+// AsVersion returns the series as a full version, "vX.Y.0", for callers
+// that have a series (eg, from a "vX.Y" string) but need a [Version]. It's the
+// same as [MajorVersion.FirstRelease], except that the zero MajorVersion
+// becomes the zero Version. The result's String parses back to it.
+func (m MajorVersion) AsVersion() Version {
+	if m.Empty() {
+		return Version{}
+	}
+	return m.FirstRelease()
+}
+
+// Patches returns the stable releases of the series from vX.Y.0 through
+// vX.Y.maxPatch, in order. It returns nil if maxPatch is negative.
+func (m MajorVersion) Patches(maxPatch int) []Version {
+	var patches []Version
+	for patch := 0; patch <= maxPatch; patch++ {
+		patches = append(patches, m.patch(patch))
+	}
+	return patches
+}
+
+// patch returns the stable release vX.Y.patch of the series.
+func (m MajorVersion) patch(patch int) Version {
+	v := Version{year: m.Year, ordinal: m.Ordinal, patch: patch, phase: Stable}
+	v.raw = v.Format("v%X.%Y.%Z")
+	return v
+}
@@ -15,6 +15,7 @@
 package version
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -28,8 +29,11 @@ func TestValidateSeries(t *testing.T) {
 		{"v24.1", false},
 		{"v24.2", false},
 		{"v24.999", false},
-		{"v0.1", false},
 		{"v24.1.0", true},
+		// year 0 is rejected, as it is by Parse
+		{"v0.1", true},
+		{"v00.1", true},
+		{"v024.1", true},
 		{"v24.0", true},
 		{"bob", true},
 		{"", true},
@@ -46,3 +50,180 @@ func TestValidateSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestMajorVersion_SuccessorPredecessor(t *testing.T) {
+	cases := []struct {
+		series          string
+		ordinalsPerYear int
+		successor       MajorVersion
+		predecessor     MajorVersion
+	}{
+		// CockroachDB's published cadence, including the move to calendar
+		// versioning after v2.1
+		{"v1.1", 0, MajorVersion{2, 1}, MajorVersion{}},
+		{"v2.1", 0, MajorVersion{19, 1}, MajorVersion{1, 1}},
+		{"v19.1", 0, MajorVersion{19, 2}, MajorVersion{2, 1}},
+		{"v19.2", 0, MajorVersion{20, 1}, MajorVersion{19, 1}},
+		{"v22.2", 0, MajorVersion{23, 1}, MajorVersion{22, 1}},
+		{"v23.2", 0, MajorVersion{24, 1}, MajorVersion{23, 1}},
+		{"v24.1", 0, MajorVersion{24, 2}, MajorVersion{23, 2}},
+		{"v24.3", 0, MajorVersion{25, 1}, MajorVersion{24, 2}},
+		{"v25.1", 0, MajorVersion{25, 2}, MajorVersion{24, 3}},
+		{"v25.4", 0, MajorVersion{26, 1}, MajorVersion{25, 3}},
+		{"v26.1", 0, MajorVersion{26, 2}, MajorVersion{25, 4}},
+
+		// an explicit cadence
+		{"v24.1", 4, MajorVersion{24, 2}, MajorVersion{23, 4}},
+		{"v24.3", 4, MajorVersion{24, 4}, MajorVersion{24, 2}},
+		{"v22.2", 2, MajorVersion{23, 1}, MajorVersion{22, 1}},
+		{"v23.1", 2, MajorVersion{23, 2}, MajorVersion{22, 2}},
+		{"v2.1", 4, MajorVersion{2, 2}, MajorVersion{1, 4}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s/%d", tc.series, tc.ordinalsPerYear), func(t *testing.T) {
+			m := MustParseMajorVersion(tc.series)
+			require.Equal(t, tc.successor, m.Successor(tc.ordinalsPerYear))
+			require.Equal(t, tc.predecessor, m.Predecessor(tc.ordinalsPerYear))
+			require.Equal(t, m, m.Successor(tc.ordinalsPerYear).Predecessor(tc.ordinalsPerYear))
+			if !tc.predecessor.Empty() {
+				require.Equal(t, m, m.Predecessor(tc.ordinalsPerYear).Successor(tc.ordinalsPerYear))
+			}
+		})
+	}
+
+	t.Run("first series", func(t *testing.T) {
+		require.True(t, MustParseMajorVersion("v1.1").Predecessor(0).Empty())
+		require.True(t, MustParseMajorVersion("v1.1").Predecessor(4).Empty())
+	})
+
+	t.Run("walk", func(t *testing.T) {
+		// stepping through the published series from v1.1 reaches v25.1 and
+		// back, with none skipped
+		var walked []string
+		for m := MustParseMajorVersion("v1.1"); !m.Empty(); m = m.Successor(0) {
+			walked = append(walked, m.String())
+			if m.Year == 25 {
+				break
+			}
+		}
+		require.Equal(t, []string{
+			"v1.1", "v2.1", "v19.1", "v19.2", "v20.1", "v20.2", "v21.1", "v21.2",
+			"v22.1", "v22.2", "v23.1", "v23.2", "v24.1", "v24.2", "v24.3", "v25.1",
+		}, walked)
+		for i := len(walked) - 1; i > 0; i-- {
+			require.Equal(t, walked[i-1], MustParseMajorVersion(walked[i]).Predecessor(0).String())
+		}
+	})
+}
+
+func TestMajorVersion_MinMax(t *testing.T) {
+	a := MustParseMajorVersion("v24.1")
+	b := MustParseMajorVersion("v24.3")
+	c := MustParseMajorVersion("v25.1")
+
+	require.Equal(t, a, a.Min(b))
+	require.Equal(t, a, b.Min(a))
+	require.Equal(t, b, a.Max(b))
+	require.Equal(t, c, c.Max(b))
+	require.Equal(t, b, b.Min(c))
+
+	// equal
+	require.Equal(t, a, a.Min(MustParseMajorVersion("v24.1")))
+	require.Equal(t, a, a.Max(MustParseMajorVersion("v24.1")))
+}
+
+func TestMajorVersion_ScheduleKey(t *testing.T) {
+	require.Equal(t, "2024H1", MustParseMajorVersion("v24.1").ScheduleKey())
+	require.Equal(t, "2024H2", MustParseMajorVersion("v24.2").ScheduleKey())
+	require.Equal(t, "2023H2", MustParseMajorVersion("v23.2").ScheduleKey())
+	require.Equal(t, "2019H1", MustParseMajorVersion("v19.1").ScheduleKey())
+
+	// ordinals past the second are keyed by release number
+	require.Equal(t, "2024R3", MustParseMajorVersion("v24.3").ScheduleKey())
+	require.Equal(t, "2025R4", MustParseMajorVersion("v25.4").ScheduleKey())
+
+	// series from before calendar versioning are keyed by their release date
+	require.Equal(t, "2017H2", MustParseMajorVersion("v1.1").ScheduleKey())
+	require.Equal(t, "2018H2", MustParseMajorVersion("v2.1").ScheduleKey())
+
+	require.Empty(t, MajorVersion{}.ScheduleKey())
+
+	// every published series has a distinct key
+	keys := map[string]MajorVersion{}
+	for m := MustParseMajorVersion("v1.1"); m.Year <= 26; m = m.Successor(0) {
+		prev, ok := keys[m.ScheduleKey()]
+		require.False(t, ok, "%s and %s share the key %s", prev, m, m.ScheduleKey())
+		keys[m.ScheduleKey()] = m
+	}
+}
+
+func TestMajorVersion_Patches(t *testing.T) {
+	patches := MustParseMajorVersion("v24.1").Patches(2)
+	require.Equal(t, []Version{MustParse("v24.1.0"), MustParse("v24.1.1"), MustParse("v24.1.2")}, patches)
+
+	require.Equal(t, []Version{MustParse("v24.1.0")}, MustParseMajorVersion("v24.1").Patches(0))
+	require.Empty(t, MustParseMajorVersion("v24.1").Patches(-1))
+}
+
+func TestMajorVersionFrom(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  string
+	}{
+		{"v25.1", "v25.1"},
+		{"v25.1.0", "v25.1"},
+		{"v25.1.2-rc.1", "v25.1"},
+		{"v24.3.0-alpha.00000000-1234-gabcdef0", "v24.3"},
+	} {
+		m, err := MajorVersionFrom(tc.input)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.want, m.String())
+	}
+
+	for _, input := range []string{"", "v25", "25.1", "v25.1.x"} {
+		_, err := MajorVersionFrom(input)
+		require.Error(t, err, input)
+	}
+
+	// ParseMajorVersion stays strict.
+	_, err := ParseMajorVersion("v25.1.0")
+	require.Error(t, err)
+}
+
+func TestMajorVersion_Contains(t *testing.T) {
+	m := MustParseMajorVersion("v24.1")
+	for _, str := range []string{"v24.1.0", "v24.1.7", "v24.1.0-rc.1", "v24.1.3-12-gabcdef1", "v24.1.0-cloudonly.1"} {
+		require.True(t, m.Contains(MustParse(str)), str)
+	}
+	for _, str := range []string{"v24.2.0", "v23.1.0", "v24.10.0"} {
+		require.False(t, m.Contains(MustParse(str)), str)
+	}
+	require.False(t, m.Contains(Version{}))
+	require.False(t, MajorVersion{}.Contains(Version{}))
+}
+
+func TestMajorVersion_FirstRelease(t *testing.T) {
+	v := MustParseMajorVersion("v24.1").FirstRelease()
+	require.Equal(t, MustParse("v24.1.0"), v)
+	require.Equal(t, "v24.1.0", v.String())
+	require.Equal(t, Stable, v.Phase())
+}
+
+func TestMajorVersion_AsVersion(t *testing.T) {
+	for _, str := range []string{"v24.1", "v1.1", "v23.2"} {
+		v := MustParseMajorVersion(str).AsVersion()
+		require.Equal(t, str+".0", v.String())
+		require.Equal(t, MustParse(v.String()), v)
+		require.Equal(t, MustParseMajorVersion(str), v.Major())
+	}
+	require.True(t, MajorVersion{}.AsVersion().Empty())
+}
+
+func TestParseMajorVersionMatchesParse(t *testing.T) {
+	for _, str := range []string{"v0.1", "v1.1", "v24.1", "v24.0", "v01.1"} {
+		_, seriesErr := ParseMajorVersion(str)
+		_, versionErr := Parse(str + ".0")
+		require.Equal(t, versionErr == nil, seriesErr == nil, str)
+	}
+}
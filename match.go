@@ -0,0 +1,64 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+)
+
+// matchPatternRE matches the patterns accepted by [Match].
+var matchPatternRE = regexp.MustCompile(`^v([1-9][0-9]*)\.(\*|[1-9][0-9]*)(?:\.(\*|0|[1-9][0-9]*))?(-stable)?$`)
+
+// Match reports whether v matches pattern, a glob-style version pattern like
+// "v24.1.*" or "v24.*". A pattern is a "vX.Y.Z" version with no suffix, where
+// the ordinal (Y) and/or patch (Z) may be replaced with a "*" wildcard that
+// matches any value in that position; "vX.*" is shorthand for "vX.*.*". All
+// versions with a matching year, ordinal, and patch match the pattern, so
+// "v24.1.*" matches "v24.1.3" as well as prereleases and builds of the v24.1
+// series, like "v24.1.0-rc.1". A trailing "-stable", as in "v24.1.*-stable",
+// restricts the pattern to stable releases: versions in the stable phase that
+// aren't `git describe` builds, so "v24.1.3" and "v24.1.3-fips" match but
+// "v24.1.0-rc.1" and "v24.1.3-12-gabcdef1" don't. The zero Version never
+// matches.
+//
+// Match returns an error if pattern is malformed. Unlike [ConstraintSet], which
+// compares versions using operators, Match only checks version components.
+func Match(pattern string, v Version) (bool, error) {
+	groups := matchPatternRE.FindStringSubmatch(pattern)
+	if groups == nil || (groups[2] != "*" && groups[3] == "") {
+		return false, errors.Newf("invalid version pattern '%s': must look like vX.Y.Z, vX.Y.*, or vX.*", pattern)
+	}
+	if v.Empty() {
+		return false, nil
+	}
+
+	matches := func(group string, value int) bool {
+		if group == "*" || group == "" {
+			return true
+		}
+		n, err := strconv.Atoi(group)
+		return err == nil && n == value
+	}
+	if !matches(groups[1], v.year) || !matches(groups[2], v.ordinal) || !matches(groups[3], v.patch) {
+		return false, nil
+	}
+	if groups[4] != "" && (v.phase != Stable || v.IsCustomBuild()) {
+		return false, nil
+	}
+	return true, nil
+}
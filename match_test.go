@@ -0,0 +1,100 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "v24.1.*",
+			matches: []string{"v24.1.0", "v24.1.3", "v24.1.0-rc.1", "v24.1.3-12-gabcdef1", "v24.1.0-cloudonly.1"},
+			misses:  []string{"v24.2.0", "v23.1.3", "v24.10.1"},
+		},
+		{
+			pattern: "v24.*",
+			matches: []string{"v24.1.0", "v24.3.7", "v24.2.0-alpha.1"},
+			misses:  []string{"v23.2.0", "v25.1.0"},
+		},
+		{
+			pattern: "v24.*.*",
+			matches: []string{"v24.1.0", "v24.3.7"},
+			misses:  []string{"v25.1.0"},
+		},
+		{
+			pattern: "v24.*.0",
+			matches: []string{"v24.1.0", "v24.2.0-rc.1"},
+			misses:  []string{"v24.1.1"},
+		},
+		{
+			pattern: "v24.1.3",
+			matches: []string{"v24.1.3", "v24.1.3-fips", "v24.1.3-12-gabcdef1"},
+			misses:  []string{"v24.1.4", "v24.1.30"},
+		},
+		{
+			pattern: "v24.1.*-stable",
+			matches: []string{"v24.1.0", "v24.1.3", "v24.1.3-fips", "v24.1.3+enterprise"},
+			misses:  []string{"v24.1.0-rc.1", "v24.1.3-12-gabcdef1", "v24.1.0-cloudonly.1", "v24.1.0-foo", "v24.2.0"},
+		},
+		{
+			pattern: "v24.*-stable",
+			matches: []string{"v24.2.1"},
+			misses:  []string{"v24.2.0-beta.1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			for _, str := range tc.matches {
+				ok, err := Match(tc.pattern, MustParse(str))
+				require.NoError(t, err)
+				require.Truef(t, ok, "expected %s to match %s", str, tc.pattern)
+			}
+			for _, str := range tc.misses {
+				ok, err := Match(tc.pattern, MustParse(str))
+				require.NoError(t, err)
+				require.Falsef(t, ok, "expected %s not to match %s", str, tc.pattern)
+			}
+			ok, err := Match(tc.pattern, Version{})
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	}
+
+	for _, pattern := range []string{
+		"",
+		"*",
+		"v*",
+		"v*.1.0",
+		"v24",
+		"v24.1",
+		"24.1.*",
+		"v24.1.*.*",
+		"v24.01.*",
+		"v24.1.x",
+		"v24.1.*-rc",
+		"v24.1.0-rc.1",
+	} {
+		_, err := Match(pattern, MustParse("v24.1.0"))
+		require.ErrorContains(t, err, "invalid version pattern", pattern)
+	}
+}
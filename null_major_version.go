@@ -0,0 +1,53 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import "database/sql/driver"
+
+// NullMajorVersion represents a NULLable release series when stored in the
+// database, much like [NullVersion]. The zero value of NullMajorVersion
+// serializes as database NULL (and vice-versa).
+type NullMajorVersion struct {
+	Valid        bool
+	MajorVersion MajorVersion
+}
+
+func NewNullMajorVersion(m MajorVersion) NullMajorVersion {
+	return NullMajorVersion{
+		Valid:        !m.Empty(),
+		MajorVersion: m,
+	}
+}
+
+// Value implements [database/sql/driver.Valuer].
+func (n NullMajorVersion) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.MajorVersion.Value()
+}
+
+// Scan implements [database/sql.Scanner].
+func (n *NullMajorVersion) Scan(value interface{}) error {
+	if value == nil {
+		*n = NullMajorVersion{}
+		return nil
+	}
+	if err := n.MajorVersion.Scan(value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
@@ -21,10 +21,40 @@ func NewNullVersion(v Version) NullVersion {
 	}
 }
 
+// Compare returns -1, 0, or +1 indicating the relative ordering of nullable
+// versions. As with SQL's default ordering, NULL (invalid) versions sort before
+// all valid versions, and are equal to each other.
+func (n NullVersion) Compare(o NullVersion) int {
+	switch {
+	case !n.Valid && !o.Valid:
+		return 0
+	case !n.Valid:
+		return -1
+	case !o.Valid:
+		return 1
+	default:
+		return n.Version.Compare(o.Version)
+	}
+}
+
+// Equals returns true if both versions are NULL, or both are valid and equal.
+func (n NullVersion) Equals(o NullVersion) bool {
+	return n.Compare(o) == 0
+}
+
+// String returns "NULL" for an invalid NullVersion, and the version string
+// otherwise.
+func (n NullVersion) String() string {
+	if !n.Valid {
+		return "NULL"
+	}
+	return n.Version.String()
+}
+
 // Value is used when serializing a NullVersion for storage in the db.
 func (n NullVersion) Value() (driver.Value, error) {
 	if n.Valid {
-		return n.Version.String(), nil
+		return n.Version.Value()
 	} else {
 		return nil, nil
 	}
@@ -44,6 +74,69 @@ func (n *NullVersion) Scan(value interface{}) error {
 	return nil
 }
 
+// nullVersionEmptyText is the text form of a valid NullVersion holding the
+// empty version. As in PostgreSQL's CSV format, a quoted empty string tells an
+// empty value apart from NULL, which is written as nothing at all.
+const nullVersionEmptyText = `""`
+
+// MarshalText implements encoding.TextMarshaler. As with Value, an invalid
+// NullVersion marshals to empty text, and a valid one to its version string.
+// Since empty text is NULL, a valid NullVersion holding the empty version
+// (which Value writes as an empty string) marshals to `""`.
+func (n NullVersion) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	if n.Version.Empty() {
+		return []byte(nullVersionEmptyText), nil
+	}
+	if err := n.Version.checkMarshalable(); err != nil {
+		return nil, err
+	}
+	return []byte(n.Version.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text unmarshals to
+// an invalid NullVersion, and `""` to a valid NullVersion holding the empty
+// version, as with Scan of an empty string; anything else must be a valid
+// version string.
+func (n *NullVersion) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*n = NullVersion{}
+		return nil
+	}
+	if string(text) == nullVersionEmptyText {
+		*n = NullVersion{Valid: true}
+		return nil
+	}
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*n = NullVersion{Valid: true, Version: v}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The encoding is the one produced by
+// the default struct encoding, {"Valid":...,"Version":{"$raw":...}}, made
+// explicit so that its handling of edge cases is pinned down: an invalid
+// NullVersion always encodes an empty version, and a valid NullVersion holding
+// the empty version encodes as {"Valid":true,"Version":{"$raw":""}}, which
+// UnmarshalJSON decodes back to the same value.
+func (n NullVersion) MarshalJSON() ([]byte, error) {
+	var raw string
+	if n.Valid {
+		if err := n.Version.checkMarshalable(); err != nil {
+			return nil, err
+		}
+		raw = n.Version.String()
+	}
+	return json.Marshal(struct {
+		Valid   bool
+		Version map[string]string
+	}{n.Valid, map[string]string{"$raw": raw}})
+}
+
 // We must implement json.Unmarshaler, because the invalid NullVersion stores an empty
 // string in the version field, and we don't want to make Version unmarshal successfully
 // from empty string (it should and does maintain the same behavior as Parse).
@@ -60,6 +153,13 @@ func (n *NullVersion) UnmarshalJSON(data []byte) error {
 		// then Version is a map like {"$raw": "vX.Y.Z"}
 		if versionMap, ok := rawMap["Version"].(map[string]interface{}); ok {
 			if rawVersion, ok := versionMap["$raw"].(string); ok {
+				if rawVersion == "" {
+					// A valid NullVersion holding the empty version; see
+					// MarshalJSON.
+					n.Valid = true
+					n.Version = Version{}
+					return nil
+				}
 				parsed, err := Parse(rawVersion)
 				if err != nil {
 					return err
@@ -72,3 +172,12 @@ func (n *NullVersion) UnmarshalJSON(data []byte) error {
 	}
 	return errors.Newf("cannot parse '%s' as NullVersion", data)
 }
+
+// NullVersions implements sort.Interface for a slice of NullVersions, ordered
+// by [NullVersion.Compare]: NULL (invalid) versions sort first, followed by
+// valid versions in version order.
+type NullVersions []NullVersion
+
+func (n NullVersions) Len() int           { return len(n) }
+func (n NullVersions) Less(i, j int) bool { return n[i].Compare(n[j]) < 0 }
+func (n NullVersions) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
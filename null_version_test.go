@@ -0,0 +1,97 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullVersionCompare(t *testing.T) {
+	null := NullVersion{}
+	v1 := NewNullVersion(MustParse("v24.1.0"))
+	v2 := NewNullVersion(MustParse("v24.2.0-rc.1"))
+
+	testCases := []struct {
+		a, b NullVersion
+		want int
+	}{
+		{null, null, 0},
+		{null, v1, -1},
+		{v1, null, 1},
+		{v1, v1, 0},
+		{v1, v2, -1},
+		{v2, v1, 1},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s vs %s", tc.a, tc.b), func(t *testing.T) {
+			require.Equal(t, tc.want, tc.a.Compare(tc.b))
+			require.Equal(t, tc.want == 0, tc.a.Equals(tc.b))
+		})
+	}
+
+	// a NULL is never equal to a valid version, even an empty one
+	require.False(t, null.Equals(NullVersion{Valid: true}))
+}
+
+func TestNullVersionString(t *testing.T) {
+	require.Equal(t, "NULL", NullVersion{}.String())
+	require.Equal(t, "v24.1.0-rc.1", NewNullVersion(MustParse("v24.1.0-rc.1")).String())
+}
+
+func TestNullVersionText(t *testing.T) {
+	text, err := NewNullVersion(MustParse("v24.1.0-rc.1")).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "v24.1.0-rc.1", string(text))
+
+	var n NullVersion
+	require.NoError(t, n.UnmarshalText(text))
+	require.Equal(t, NewNullVersion(MustParse("v24.1.0-rc.1")), n)
+
+	text, err = NullVersion{}.MarshalText()
+	require.NoError(t, err)
+	require.Empty(t, text)
+	require.NoError(t, n.UnmarshalText(text))
+	require.False(t, n.Valid)
+	require.Equal(t, NullVersion{}, n)
+
+	_, err = NewNullVersion(Latest).MarshalText()
+	require.ErrorContains(t, err, "latest")
+	require.Error(t, n.UnmarshalText([]byte("latest")))
+
+	require.Error(t, n.UnmarshalText([]byte("24.1.0")))
+}
+
+func TestNullVersionTextValidEmpty(t *testing.T) {
+	// a valid NullVersion holding the empty version round-trips as valid, as
+	// it does through Value and Scan
+	validEmpty := NullVersion{Valid: true}
+	text, err := validEmpty.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, `""`, string(text))
+	var n NullVersion
+	require.NoError(t, n.UnmarshalText(text))
+	require.Equal(t, validEmpty, n)
+
+	value, err := validEmpty.Value()
+	require.NoError(t, err)
+	var scanned NullVersion
+	require.NoError(t, scanned.Scan(value))
+	require.Equal(t, n, scanned)
+}
+
+func TestNullVersionsSort(t *testing.T) {
+	null := NullVersion{}
+	v1 := NewNullVersion(MustParse("v23.2.4"))
+	v2 := NewNullVersion(MustParse("v24.1.0-rc.1"))
+	v3 := NewNullVersion(MustParse("v24.1.0"))
+
+	vs := NullVersions{v3, null, v1, null, v2}
+	sort.Sort(vs)
+	require.Equal(t, NullVersions{null, null, v1, v2, v3}, vs)
+
+	vs = NullVersions{v2, v1}
+	sort.Sort(vs)
+	require.Equal(t, NullVersions{v1, v2}, vs)
+}
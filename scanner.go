@@ -0,0 +1,83 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Scanner reads newline-delimited versions from an [io.Reader], one line at a
+// time, so that long lists of versions can be processed without reading them
+// into memory all at once. Leading and trailing whitespace (including "\r") is
+// ignored, and blank lines are skipped. Its use mirrors [bufio.Scanner]:
+//
+//	s := version.NewScanner(r)
+//	for s.Scan() {
+//		v, err := s.Version()
+//		...
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Scanner struct {
+	lines   *bufio.Scanner
+	lineNum int
+	version Version
+	err     error
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{lines: bufio.NewScanner(r)}
+}
+
+// Scan advances to the next non-blank line, which is then available from
+// [Scanner.Version]. It returns false when there are no more lines, either
+// because the end of the input was reached or because reading failed; in the
+// latter case, [Scanner.Err] returns the error. A line that isn't a valid
+// version doesn't stop the scan.
+func (s *Scanner) Scan() bool {
+	for s.lines.Scan() {
+		s.lineNum++
+		line := strings.TrimSpace(s.lines.Text())
+		if line == "" {
+			continue
+		}
+		s.version, s.err = Parse(line)
+		if s.err != nil {
+			s.err = errors.Wrapf(s.err, "line %d", s.lineNum)
+		}
+		return true
+	}
+	s.version, s.err = Version{}, nil
+	return false
+}
+
+// Version returns the version on the current line, or an error (which
+// includes the line number) if it isn't a valid version.
+func (s *Scanner) Version() (Version, error) {
+	return s.version, s.err
+}
+
+// Err returns the first error encountered reading the input, or nil if the
+// end of the input was reached. Parse errors are not included; see
+// [Scanner.Version].
+func (s *Scanner) Err() error {
+	return s.lines.Err()
+}
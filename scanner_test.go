@@ -0,0 +1,69 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner(t *testing.T) {
+	input := "v24.1.0\n\n  v24.1.0-rc.1\r\nnot-a-version\n   \nv23.2.4"
+	s := NewScanner(strings.NewReader(input))
+
+	var versions []string
+	var errs []string
+	for s.Scan() {
+		v, err := s.Version()
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		versions = append(versions, v.String())
+	}
+	require.NoError(t, s.Err())
+	require.Equal(t, []string{"v24.1.0", "v24.1.0-rc.1", "v23.2.4"}, versions)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0], "line 4")
+	require.Contains(t, errs[0], "invalid version string 'not-a-version'")
+
+	// scanning stops cleanly at EOF
+	require.False(t, s.Scan())
+	v, err := s.Version()
+	require.NoError(t, err)
+	require.True(t, v.Empty())
+
+	s = NewScanner(strings.NewReader(""))
+	require.False(t, s.Scan())
+	require.NoError(t, s.Err())
+}
+
+func TestScannerReadError(t *testing.T) {
+	readErr := errors.New("boom")
+	s := NewScanner(io.MultiReader(strings.NewReader("v24.1.0\n"), iotest.ErrReader(readErr)))
+
+	require.True(t, s.Scan())
+	v, err := s.Version()
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.0"), v)
+
+	require.False(t, s.Scan())
+	require.ErrorIs(t, s.Err(), readErr)
+}
@@ -22,6 +22,17 @@ func TestVersionScan(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, scanned.Empty())
 	})
+
+	t.Run("latest", func(t *testing.T) {
+		_, err := Latest.Value()
+		require.ErrorContains(t, err, "latest")
+		_, err = NewNullVersion(Latest).Value()
+		require.ErrorContains(t, err, "latest")
+
+		var scanned Version
+		err = scanned.Scan("latest")
+		require.Error(t, err)
+	})
 }
 
 func TestNullVersionScan(t *testing.T) {
@@ -51,3 +62,107 @@ func TestNullVersionScan(t *testing.T) {
 		require.True(t, scanned.Version.Empty())
 	})
 }
+
+func TestLenientVersionScan(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		v := MustParse("v20.1.2-alpha.3-cloudonly.4")
+
+		var scanned LenientVersion
+		err := scanned.Scan(v.String())
+		require.NoError(t, err)
+		require.NoError(t, scanned.ScanErr)
+		require.Equal(t, v, scanned.Version)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var scanned LenientVersion
+		err := scanned.Scan("")
+		require.NoError(t, err)
+		require.NoError(t, scanned.ScanErr)
+		require.True(t, scanned.Version.Empty())
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		scanned := LenientVersion{Version: MustParse("v24.1.0")}
+		err := scanned.Scan("not a version")
+		require.NoError(t, err)
+		require.ErrorContains(t, scanned.ScanErr, "invalid version string")
+		require.True(t, scanned.Version.Empty())
+
+		err = scanned.Scan(123)
+		require.NoError(t, err)
+		require.ErrorContains(t, scanned.ScanErr, "cannot convert int to Version")
+		require.True(t, scanned.Version.Empty())
+
+		// a subsequent good scan clears the error
+		err = scanned.Scan("v24.1.0")
+		require.NoError(t, err)
+		require.NoError(t, scanned.ScanErr)
+		require.Equal(t, MustParse("v24.1.0"), scanned.Version)
+	})
+}
+
+func TestMajorVersionScan(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		m := MustParseMajorVersion("v24.1")
+
+		value, err := m.Value()
+		require.NoError(t, err)
+		require.Equal(t, "v24.1", value)
+
+		var scanned MajorVersion
+		require.NoError(t, scanned.Scan(value))
+		require.Equal(t, m, scanned)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		value, err := MajorVersion{}.Value()
+		require.NoError(t, err)
+		require.Equal(t, "", value)
+
+		scanned := MustParseMajorVersion("v24.1")
+		require.NoError(t, scanned.Scan(""))
+		require.True(t, scanned.Empty())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var scanned MajorVersion
+		require.Error(t, scanned.Scan(nil))
+		require.Error(t, scanned.Scan(24))
+		require.ErrorContains(t, scanned.Scan("v24.1.0"), "not a valid CockroachDB major version")
+	})
+}
+
+func TestNullMajorVersionScan(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		nm := NewNullMajorVersion(MustParseMajorVersion("v24.1"))
+
+		value, err := nm.Value()
+		require.NoError(t, err)
+		require.Equal(t, "v24.1", value)
+
+		var scanned NullMajorVersion
+		require.NoError(t, scanned.Scan(value))
+		require.Equal(t, nm, scanned)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		value, err := NullMajorVersion{}.Value()
+		require.NoError(t, err)
+		require.Nil(t, value)
+
+		scanned := NewNullMajorVersion(MustParseMajorVersion("v24.1"))
+		require.NoError(t, scanned.Scan(nil))
+		require.False(t, scanned.Valid)
+		require.True(t, scanned.MajorVersion.Empty())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.False(t, NewNullMajorVersion(MajorVersion{}).Valid)
+
+		var scanned NullMajorVersion
+		require.NoError(t, scanned.Scan(""))
+		require.True(t, scanned.Valid)
+		require.True(t, scanned.MajorVersion.Empty())
+	})
+}
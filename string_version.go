@@ -0,0 +1,52 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import "encoding/json"
+
+// StringVersion is a Version that is marshaled to JSON as a bare string, eg
+// "v24.1.0", rather than the {"$raw": "v24.1.0"} envelope used by Version.
+// It's intended for public-facing APIs, where consumers expect a plain string.
+// The zero version is marshaled as "", and "" is unmarshaled as the zero
+// version.
+type StringVersion struct {
+	Version
+}
+
+// MarshalJSON implements [encoding/json.Marshaler].
+func (v StringVersion) MarshalJSON() ([]byte, error) {
+	if err := v.checkMarshalable(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v.raw)
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler].
+func (v *StringVersion) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		*v = StringVersion{}
+		return nil
+	}
+	parsed, err := Parse(str)
+	if err != nil {
+		return err
+	}
+	*v = StringVersion{parsed}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import "slices"
+
+// A TimelineEntry is an entry in a release timeline, which mixes release
+// series markers ([SeriesEntry]) and concrete releases ([ReleaseEntry]).
+type TimelineEntry interface {
+	// Series returns the release series the entry belongs to.
+	Series() MajorVersion
+	// release returns the entry's version, or false for series markers.
+	release() (Version, bool)
+}
+
+// SeriesEntry is a [TimelineEntry] marking the start of a release series.
+type SeriesEntry MajorVersion
+
+var _ TimelineEntry = SeriesEntry{}
+
+// Series implements [TimelineEntry].
+func (s SeriesEntry) Series() MajorVersion {
+	return MajorVersion(s)
+}
+
+func (s SeriesEntry) release() (Version, bool) {
+	return Version{}, false
+}
+
+// ReleaseEntry is a [TimelineEntry] for a concrete release.
+type ReleaseEntry Version
+
+var _ TimelineEntry = ReleaseEntry{}
+
+// Series implements [TimelineEntry].
+func (r ReleaseEntry) Series() MajorVersion {
+	return Version(r).Major()
+}
+
+func (r ReleaseEntry) release() (Version, bool) {
+	return Version(r), true
+}
+
+// SortTimeline sorts a timeline in ascending order. Entries are ordered by
+// release series first, and a series marker sorts immediately before the
+// first release of its series, including any pre-releases of it (eg, v24.1
+// sorts before "v24.1.0-alpha.1"). Releases within a series are ordered by
+// [Version.Compare]. The sort is stable, so duplicate series markers keep
+// their original relative order.
+func SortTimeline(entries []TimelineEntry) {
+	slices.SortStableFunc(entries, func(a, b TimelineEntry) int {
+		if rslt := a.Series().Compare(b.Series()); rslt != 0 {
+			return rslt
+		}
+		av, aIsRelease := a.release()
+		bv, bIsRelease := b.release()
+		switch {
+		case aIsRelease && bIsRelease:
+			return av.Compare(bv)
+		case aIsRelease:
+			return 1
+		case bIsRelease:
+			return -1
+		default:
+			return 0
+		}
+	})
+}
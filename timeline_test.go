@@ -0,0 +1,47 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortTimeline(t *testing.T) {
+	series := func(str string) TimelineEntry { return SeriesEntry(MustParseMajorVersion(str)) }
+	release := func(str string) TimelineEntry { return ReleaseEntry(MustParse(str)) }
+
+	want := []TimelineEntry{
+		series("v23.2"),
+		release("v23.2.0"),
+		release("v23.2.5"),
+		series("v24.1"),
+		release("v24.1.0-alpha.1"),
+		release("v24.1.0-rc.1"),
+		release("v24.1.0"),
+		release("v24.1.1"),
+		release("v24.2.0-beta.1"),
+		series("v24.3"),
+	}
+
+	entries := make([]TimelineEntry, len(want))
+	for a, b := range rand.Perm(len(want)) {
+		entries[a] = want[b]
+	}
+	SortTimeline(entries)
+	require.Equal(t, want, entries)
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// These errors describe common mistakes in version strings. Errors returned by
+// [Parse], [ParseBounded], and [Validate] wrap them when the likely cause can
+// be identified, so callers can test for them with [errors.Is].
+var (
+	ErrMissingPrefix    = errors.New(`version must start with "v"`)
+	ErrMissingComponent = errors.New("version must have three components, vX.Y.Z")
+	ErrExtraComponent   = errors.New("version must have only three components, vX.Y.Z")
+	ErrLeadingZero      = errors.New("version components must not have leading zeros")
+	ErrUnknownPhase     = errors.New(`phase must be one of "alpha", "beta", "rc", or "cloudonly"`)
+	ErrUppercasePhase   = errors.New("phase must be lowercase")
+	ErrYearOutOfRange   = errors.New("version year is out of range")
+)
+
+// phaseLikeRE matches adhoc labels that look like a phase and ordinal, eg
+// "RC.1" or "beta1".
+var phaseLikeRE = regexp.MustCompile(`^(?P<word>[a-zA-Z]+)(?P<dot>\.?)(?P<ordinal>[0-9]+)$`)
+
+// Validate returns nil if str is a well-formed version string, or an error
+// describing the likely problem otherwise. It is stricter than [Parse]:
+// besides rejecting everything Parse rejects, it rejects suffixes that look
+// like a misspelled or miscapitalized phase (eg "v24.1.0-RC.1",
+// "v24.1.0-beta1", or "v24.1.0-alhpa.1"), which Parse accepts as adhoc labels.
+// A misspelling is a word one edit (an inserted, deleted, or substituted
+// letter, or two swapped adjacent letters) away from a phase name or a
+// registered alias; other labels, like "v24.1.0-hotfix2", are accepted. Use
+// it to check versions typed in by users.
+func Validate(str string) error {
+	v, err := Parse(str)
+	if err != nil {
+		return err
+	}
+	if v.phase != Adhoc || strings.HasPrefix(str, "sha256:") {
+		return nil
+	}
+
+	matches := phaseLikeRE.FindStringSubmatch(v.adhocLabel)
+	if matches == nil {
+		return nil
+	}
+	word, dot, ordinal := matches[1], matches[2], matches[3]
+	lower := strings.ToLower(word)
+	if _, ok := lookupPhase(lower); !ok {
+		if !isMisspelledPhase(lower) {
+			return nil
+		}
+		return errors.Wrapf(ErrUnknownPhase, "invalid version string '%s': unknown phase '%s'", str, word)
+	}
+	suggestion := v.Format("v%X.%Y.%Z-") + lower + "." + ordinal
+	if word != lower {
+		return errors.Wrapf(ErrUppercasePhase, "invalid version string '%s' (did you mean '%s'?)", str, suggestion)
+	}
+	if dot == "" {
+		return errors.Wrapf(ErrUnknownPhase, "invalid version string '%s': unknown phase '%s%s' (did you mean '%s'?)",
+			str, word, ordinal, suggestion)
+	}
+	return nil
+}
+
+// isMisspelledPhase returns true if word, which isn't a phase name, is one edit
+// away from a phase name or alias. Single letters aren't considered, since
+// they're a single edit away from too many names.
+func isMisspelledPhase(word string) bool {
+	if len(word) < 2 {
+		return false
+	}
+	preReleasePhasesMu.RLock()
+	defer preReleasePhasesMu.RUnlock()
+	for name := range preReleasePhases {
+		if withinOneEdit(word, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinOneEdit returns true if a and b differ by at most one inserted,
+// deleted, or substituted byte, or one transposition of adjacent bytes.
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	switch len(b) - len(a) {
+	case 0:
+		if i == len(a) || a[i+1:] == b[i+1:] {
+			return true
+		}
+		return i+1 < len(a) && a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+	case 1:
+		return a[i:] == b[i+1:]
+	default:
+		return false
+	}
+}
+
+// diagnose returns an error wrapping one of the sentinel errors above if the
+// likely reason str failed to parse can be identified, or nil otherwise.
+func diagnose(str string) error {
+	if strings.TrimSpace(str) != str || strings.HasPrefix(str, "sha256:") {
+		return nil
+	}
+	if !strings.HasPrefix(str, "v") {
+		suggestion := "v" + strings.TrimPrefix(str, "V")
+		if _, err := Parse(suggestion); err == nil {
+			return errors.Wrapf(ErrMissingPrefix, "invalid version string '%s' (did you mean '%s'?)", str, suggestion)
+		}
+		return errors.Wrapf(ErrMissingPrefix, "invalid version string '%s'", str)
+	}
+
+	core, _, _ := strings.Cut(str[1:], "-")
+	core, _, _ = strings.Cut(core, "+")
+	components := strings.Split(core, ".")
+	if len(components) < 3 {
+		return errors.Wrapf(ErrMissingComponent, "invalid version string '%s'", str)
+	}
+	if len(components) > 3 {
+		return errors.Wrapf(ErrExtraComponent, "invalid version string '%s'", str)
+	}
+	for _, component := range components {
+		if len(component) > 1 && component[0] == '0' {
+			return errors.Wrapf(ErrLeadingZero, "invalid version string '%s'", str)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiagnosis(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  error
+		msg   string
+	}{
+		{"24.1.0", ErrMissingPrefix, "did you mean 'v24.1.0'?"},
+		{"V24.1.0-rc.1", ErrMissingPrefix, "did you mean 'v24.1.0-rc.1'?"},
+		{"24.1", ErrMissingPrefix, ""},
+		{"v24.1", ErrMissingComponent, ""},
+		{"v24.1-beta.1", ErrMissingComponent, ""},
+		{"v24.1.0.1", ErrExtraComponent, ""},
+		{"v1.2.3.beta", ErrExtraComponent, ""},
+		{"v01.2.3", ErrLeadingZero, ""},
+		{"v1.2.03-rc.1", ErrLeadingZero, ""},
+
+		// the original string is reported, including any build metadata
+		{"v24.1+meta", ErrMissingComponent, "'v24.1+meta'"},
+		{"24.1.0+meta", ErrMissingPrefix, "did you mean 'v24.1.0+meta'?"},
+		{"v24.01.0+meta", ErrLeadingZero, "'v24.01.0+meta'"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			require.Error(t, err)
+			require.Truef(t, errors.Is(err, tc.want), "expected %v, got %v", tc.want, err)
+			require.ErrorContains(t, err, "invalid version string")
+			require.ErrorContains(t, err, tc.msg)
+		})
+	}
+
+	// errors without an identifiable cause are unchanged
+	for _, input := range []string{" v1.0.0", "v1.2.3-beta$", "v0.1.0", "v1.2.beta", "v1.2.3-beta$+meta"} {
+		_, err := Parse(input)
+		require.EqualError(t, err, "invalid version string '"+input+"'")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, input := range []string{
+		"v24.1.0",
+		"v24.1.0-rc.1",
+		"v24.1.0-alpha.1-cloudonly.2",
+		"v23.1.11-cloudonly2",
+		"v23.1.0-swenson-mr-4",
+		"v24.1.0-rc.1-incompat",
+		"v24.1.0-hotfix2",
+		"v24.1.0-hotfix.2",
+		"v24.1.0-build123",
+		"v24.1.0-c1",
+		"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+	} {
+		require.NoError(t, Validate(input), input)
+	}
+
+	for _, tc := range []struct {
+		input string
+		want  error
+		msg   string
+	}{
+		{"24.1.0", ErrMissingPrefix, "did you mean 'v24.1.0'?"},
+		{"v24.1", ErrMissingComponent, ""},
+		{"v24.1.0-RC.1", ErrUppercasePhase, "did you mean 'v24.1.0-rc.1'?"},
+		{"v24.1.0-Beta2", ErrUppercasePhase, "did you mean 'v24.1.0-beta.2'?"},
+		{"v24.1.0-beta2", ErrUnknownPhase, "did you mean 'v24.1.0-beta.2'?"},
+		{"v24.1.0-alhpa.1", ErrUnknownPhase, "unknown phase 'alhpa'"},
+		{"v24.1.0-bta.1", ErrUnknownPhase, "unknown phase 'bta'"},
+		{"v24.1.0-betta2", ErrUnknownPhase, "unknown phase 'betta'"},
+		{"v24.1.0-rcc.1", ErrUnknownPhase, "unknown phase 'rcc'"},
+		{"v24.1.0-Cloudonyl.1", ErrUnknownPhase, "unknown phase 'Cloudonyl'"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			err := Validate(tc.input)
+			require.Error(t, err)
+			require.Truef(t, errors.Is(err, tc.want), "expected %v, got %v", tc.want, err)
+			require.ErrorContains(t, err, tc.msg)
+		})
+	}
+}
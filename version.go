@@ -17,11 +17,16 @@ package version
 import (
 	"cmp"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
@@ -29,23 +34,99 @@ import (
 
 var _ redact.SafeFormatter = Version{}
 
-type releasePhase int
+// Phase is the release phase of a version, eg alpha or rc. Phases are declared
+// in the order they sort in; see [Version.Compare].
+type Phase int
 
 const (
-	alpha     = releasePhase(1)
-	beta      = releasePhase(2)
-	rc        = releasePhase(3)
-	cloudonly = releasePhase(4)
-	stable    = releasePhase(5)
-	adhoc     = releasePhase(6)
+	Alpha     = Phase(1)
+	Beta      = Phase(2)
+	RC        = Phase(3)
+	CloudOnly = Phase(4)
+	Stable    = Phase(5)
+	Adhoc     = Phase(6)
 )
 
+// phaseNames are the names of each phase as they appear in version strings.
+var phaseNames = map[Phase]string{
+	Alpha:     "alpha",
+	Beta:      "beta",
+	RC:        "rc",
+	CloudOnly: "cloudonly",
+	Adhoc:     "",
+	Stable:    "",
+}
+
+// Phases returns the phases in the order they sort in. If includeGA is false,
+// only the pre-release phases are returned: alpha, beta, rc, and cloudonly. If
+// it's true, they're followed by [Stable] and [Adhoc], which don't appear as a
+// phase name in version strings but are the phases of "v24.1.0" and
+// "v24.1.0-foo", respectively.
+func Phases(includeGA bool) []Phase {
+	phases := []Phase{Alpha, Beta, RC, CloudOnly}
+	if includeGA {
+		phases = append(phases, Stable, Adhoc)
+	}
+	return phases
+}
+
+// String returns the phase's name: its name in version strings for the
+// pre-release phases (eg "rc"), and "stable" or "adhoc" for the others.
+func (p Phase) String() string {
+	switch p {
+	case Stable:
+		return "stable"
+	case Adhoc:
+		return "adhoc"
+	}
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Phase(%d)", int(p))
+}
+
+// MarshalText implements [encoding.TextMarshaler], writing the phase's name
+// (see [Phase.String]), so that phases can be used in configuration files. An
+// error is returned for a value that isn't one of the declared phases.
+func (p Phase) MarshalText() ([]byte, error) {
+	if _, ok := phaseNames[p]; !ok {
+		return nil, errors.Newf("invalid phase %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. It accepts the names
+// returned by [Phase.String], as well as any aliases registered with
+// [RegisterPhaseAlias]; other names are an error.
+func (p *Phase) UnmarshalText(text []byte) error {
+	name := string(text)
+	switch name {
+	case "stable":
+		*p = Stable
+		return nil
+	case "adhoc":
+		*p = Adhoc
+		return nil
+	}
+	phase, ok := lookupPhase(name)
+	if !ok {
+		return errors.Newf("unknown phase '%s'", name)
+	}
+	*p = phase
+	return nil
+}
+
 // Version represents a CockroachDB (binary) version. Versions consist of three parts:
 // a major version, written as "vX.Y" (which is typically the year and release number
 // within the year), a patch version (the "Z" in "vX.Y.Z"), and sometimes one or more
 // phases, sub-phases, and other suffixes. Note that CockroachDB versions are not
 // semantic versions! You must use this package to parse and compare versions, in
 // order to account for the variety of versions currently or historically in use.
+//
+// Don't compare versions with == or [reflect.DeepEqual]: a Version keeps the
+// string it was parsed from, so equal versions spelled differently (eg
+// "v24.1.0-cloudonly.1" and "v24.1.0-cloudonly-rc1") aren't identical structs.
+// Use [Version.Equals] or [Version.Compare] instead.
 type Version struct {
 	// A version is composed of many (possible) fields. See [Parse] for details of
 	// how a version string becomes these many fields. For comparison purposes, versions
@@ -53,25 +134,83 @@ type Version struct {
 	// the fields are compared in the order listed here, and the earliest field with
 	// a difference determines the relative ordering of two unequal versions.
 	//
-	// The reference order: year, ordinal, patch, phase, phaseOrdinal, phaseSubOrdinal, customOrdinal
+	// The reference order: latest, year, ordinal, patch, phase, phaseOrdinal, phaseSubOrdinal,
+	// customOrdinal, adhocLabel, incompat, fips. Build metadata is not compared.
+	//
+	// latest is set only for the symbolic [Latest] version; see [ParseSymbolic]
+	latest                                       bool
 	year, ordinal, patch                         int
-	phase                                        releasePhase
+	phase                                        Phase
 	phaseOrdinal, phaseSubOrdinal, customOrdinal int
 	adhocLabel                                   string
+	// incompat is set for builds that intentionally break wire compatibility
+	incompat bool
+	// fips is set for FIPS-compliant builds, marked with "-fips". It's the
+	// final tiebreak when comparing versions.
+	fips bool
+	// buildMetadata is the semver-style "+<metadata>" suffix, eg "enterprise"
+	buildMetadata string
 	// raw is the original, unprocessed string this Version was created with
 	raw string
 }
 
-// Major returns the version's MajorVersion (the "vX.Y" part)
+// Major returns the version's MajorVersion (the "vX.Y" part). Like the other
+// field accessors, it panics for the symbolic [Latest] version.
 func (v Version) Major() MajorVersion {
+	v.checkNotLatest()
 	return MajorVersion{Year: v.year, Ordinal: v.ordinal}
 }
 
 // Patch returns the version's patch number.
 func (v Version) Patch() int {
+	v.checkNotLatest()
 	return v.patch
 }
 
+// Phase returns the version's release phase.
+func (v Version) Phase() Phase {
+	v.checkNotLatest()
+	return v.phase
+}
+
+// PhaseOrdinal returns the version's phase ordinal (eg, the 1 in
+// "v24.1.0-rc.1"), or 0 if it has none.
+func (v Version) PhaseOrdinal() int {
+	v.checkNotLatest()
+	return v.phaseOrdinal
+}
+
+// PhaseSubOrdinal returns the version's phase sub-ordinal (eg, the 2 in
+// "v24.1.0-rc.1-cloudonly.2"), or 0 if it has none.
+func (v Version) PhaseSubOrdinal() int {
+	v.checkNotLatest()
+	return v.phaseSubOrdinal
+}
+
+// CustomOrdinal returns the version's adhoc build ordinal (eg, the 12 in
+// "v24.1.0-12-gabcdef"), or 0 if it has none.
+func (v Version) CustomOrdinal() int {
+	v.checkNotLatest()
+	return v.customOrdinal
+}
+
+// BuildMetadata returns the version's build metadata (eg, the "enterprise" in
+// "v24.1.0+enterprise"), or "" if it has none. Build metadata is ignored when
+// comparing versions. Adhoc builds have no build metadata: historical adhoc
+// labels may contain "+", so in "v24.1.0-foo+a", the label is "foo+a".
+func (v Version) BuildMetadata() string {
+	return v.buildMetadata
+}
+
+// FinalizationSeries returns the release series a cluster running this binary
+// version would finalize its upgrade to. For GA (stable, cloudonly, and adhoc)
+// builds this is simply the version's own series. Prereleases are named after
+// the release they lead up to (eg, "v24.1.0-rc.1" precedes "v24.1.0"), so a
+// prerelease binary also targets its own "vX.Y" series, not the previous one.
+func (v Version) FinalizationSeries() MajorVersion {
+	return v.Major()
+}
+
 // Format returns a string populated with parts of the version, using placeholders
 // similar to the fmt package. The following placeholders are supported:
 //
@@ -84,27 +223,22 @@ func (v Version) Patch() int {
 // - %s: phase sub-ordinal (eg the 2 in "v24.1.0-rc.1-cloudonly.2")
 // - %n: adhoc build ordinal (eg the 12 in "v24.1.0-12-gabcdef")
 // - %%: literal "%"
+//
+// Format panics for the symbolic [Latest] version, which has none of these
+// fields.
 func (v Version) Format(formatStr string) string {
+	v.checkNotLatest()
 	placeholderRe := regexp.MustCompile("%[^%XYZpPosn]")
 	placeholders := placeholderRe.FindAllString(formatStr, -1)
 	if len(placeholders) > 0 {
 		panic(fmt.Sprintf("unknown placeholders in format string: %s", strings.Join(placeholders, ", ")))
 	}
 
-	phaseName := map[releasePhase]string{
-		alpha:     "alpha",
-		beta:      "beta",
-		rc:        "rc",
-		cloudonly: "cloudonly",
-		adhoc:     "",
-		stable:    "",
-	}
-
 	formatStr = strings.ReplaceAll(formatStr, "%X", strconv.Itoa(v.year))
 	formatStr = strings.ReplaceAll(formatStr, "%Y", strconv.Itoa(v.ordinal))
 	formatStr = strings.ReplaceAll(formatStr, "%Z", strconv.Itoa(v.patch))
 	formatStr = strings.ReplaceAll(formatStr, "%p", strconv.Itoa(int(v.phase)))
-	formatStr = strings.ReplaceAll(formatStr, "%P", phaseName[v.phase])
+	formatStr = strings.ReplaceAll(formatStr, "%P", phaseNames[v.phase])
 	formatStr = strings.ReplaceAll(formatStr, "%o", strconv.Itoa(v.phaseOrdinal))
 	formatStr = strings.ReplaceAll(formatStr, "%s", strconv.Itoa(v.phaseSubOrdinal))
 	formatStr = strings.ReplaceAll(formatStr, "%n", strconv.Itoa(v.customOrdinal))
@@ -112,8 +246,126 @@ func (v Version) Format(formatStr string) string {
 	return formatStr
 }
 
+// FormatTemplate renders the version using a [text/template] template. The
+// following fields are available to the template:
+//
+// - .Year, .Ordinal, .Patch: the "X", "Y", and "Z" in "vX.Y.Z"
+// - .Phase: phase name (one of "alpha", "beta", "rc", "cloudonly", or empty)
+// - .PhaseOrdinal: phase ordinal (eg, the 1 in "v24.1.0-rc.1")
+// - .PhaseSubOrdinal: phase sub-ordinal (eg the 2 in "v24.1.0-rc.1-cloudonly.2")
+// - .CustomOrdinal: adhoc build ordinal (eg the 12 in "v24.1.0-12-gabcdef")
+// - .AdhocLabel: the label of an adhoc build (eg "foo" in "v24.1.0-foo")
+// - .Incompat: whether the build breaks wire compatibility (see [Version.BreaksWireCompat])
+// - .BuildMetadata: build metadata (eg "enterprise" in "v24.1.0+enterprise")
+// - .Raw: the original version string
+//
+// For example, "{{.Year}}.{{.Ordinal}}-custom" renders "v24.1.3" as "24.1-custom".
+// Errors parsing or executing the template are returned, as is an error for
+// the symbolic [Latest] version, which has none of these fields.
+func (v Version) FormatTemplate(tmpl string) (string, error) {
+	if v.latest {
+		return "", errors.New("cannot format the symbolic version latest")
+	}
+	t, err := template.New("version").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing version template")
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, v.fields()); err != nil {
+		return "", errors.Wrap(err, "executing version template")
+	}
+	return sb.String(), nil
+}
+
+// fields returns the version's fields, keyed by name.
+func (v Version) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"Year":            v.year,
+		"Ordinal":         v.ordinal,
+		"Patch":           v.patch,
+		"Phase":           phaseNames[v.phase],
+		"PhaseOrdinal":    v.phaseOrdinal,
+		"PhaseSubOrdinal": v.phaseSubOrdinal,
+		"CustomOrdinal":   v.customOrdinal,
+		"AdhocLabel":      v.adhocLabel,
+		"Incompat":        v.incompat,
+		"BuildMetadata":   v.buildMetadata,
+		"Raw":             v.raw,
+	}
+}
+
+// Normalize returns an equal version whose string form is rebuilt from its
+// parsed fields, so that equal versions spelled differently (eg,
+// "v24.1.0-cloudonly-rc1" and "v24.1.0-cloudonly.1") normalize to the same
+// string. The canonical forms are:
+//
+// - stable: "vX.Y.Z"
+// - pre-releases: "vX.Y.Z-<phase>.N", plus "-cloudonly.M" for a sub-ordinal
+// - cloudonly: "vX.Y.Z-cloudonly.N"
+// - adhoc labels: "vX.Y.Z-<label>"
+//
+// followed by "-fips" for FIPS builds, "-incompat" for builds that break wire
+// compatibility, and "+<metadata>" for builds with build metadata.
+//
+// Some versions can't be losslessly rebuilt from their fields, because the
+// fields don't record all of the original string. Builds described by
+// `git describe` ("vX.Y.Z-N-g<sha>") and image digests
+// ("sha256:<hash>:latest-vX.Y-build") are returned unchanged.
+func (v Version) Normalize() Version {
+	if str, ok := v.canonical(); ok {
+		return MustParse(str)
+	}
+	return v
+}
+
+// canonical returns the canonical string form of v (see [Version.Normalize]),
+// and whether v can be losslessly rendered in that form.
+func (v Version) canonical() (string, bool) {
+	if v.Empty() || v.IsCustomBuild() || strings.HasPrefix(v.raw, "sha256:") {
+		return "", false
+	}
+	var str string
+	switch v.phase {
+	case Alpha, Beta, RC:
+		str = v.Format("v%X.%Y.%Z-%P.%o")
+		if v.phaseSubOrdinal > 0 {
+			str += v.Format("-cloudonly.%s")
+		}
+	case CloudOnly:
+		str = v.Format("v%X.%Y.%Z-cloudonly.%o")
+	case Adhoc:
+		str = v.Format("v%X.%Y.%Z-") + v.adhocLabel
+	default:
+		str = v.Format("v%X.%Y.%Z")
+	}
+	str = v.appendSuffixes(str)
+	if parsed, err := Parse(str); err != nil || !parsed.Equals(v) {
+		return "", false
+	}
+	return str, true
+}
+
+// appendSuffixes appends v's "-fips" and "-incompat" markers and its build
+// metadata, if any, to str, a version string rebuilt from v's other fields.
+func (v Version) appendSuffixes(str string) string {
+	// -fips precedes -incompat, which Parse strips first
+	if v.fips {
+		str += "-fips"
+	}
+	if v.incompat {
+		str += "-incompat"
+	}
+	if v.buildMetadata != "" {
+		str += "+" + v.buildMetadata
+	}
+	return str
+}
+
 // Value implements [database/sql/driver.Valuer].
 func (v Version) Value() (driver.Value, error) {
+	if err := v.checkMarshalable(); err != nil {
+		return nil, err
+	}
 	return v.raw, nil
 }
 
@@ -141,20 +393,91 @@ func (v *Version) Scan(value interface{}) error {
 
 // MarshalJSON implements [encoding/json.Marshaler].
 func (v Version) MarshalJSON() ([]byte, error) {
+	if err := v.checkMarshalable(); err != nil {
+		return nil, err
+	}
 	jsonData := map[string]string{
 		"$raw": v.raw,
 	}
 	return json.Marshal(jsonData)
 }
 
-// UnmarshalJSON implements [encoding/json.Unmarshaler].
+// verboseJSON is the form written by [Version.MarshalJSONVerbose].
+type verboseJSON struct {
+	Raw             string `json:"raw"`
+	Year            int    `json:"year"`
+	Ordinal         int    `json:"ordinal"`
+	Patch           int    `json:"patch"`
+	Phase           string `json:"phase,omitempty"`
+	PhaseOrdinal    int    `json:"phaseOrdinal,omitempty"`
+	PhaseSubOrdinal int    `json:"phaseSubOrdinal,omitempty"`
+	CustomOrdinal   int    `json:"customOrdinal,omitempty"`
+	AdhocLabel      string `json:"adhocLabel,omitempty"`
+	Incompat        bool   `json:"incompat,omitempty"`
+	FIPS            bool   `json:"fips,omitempty"`
+	BuildMetadata   string `json:"buildMetadata,omitempty"`
+}
+
+// MarshalJSONVerbose is an alternative to MarshalJSON which also writes the
+// version's parsed fields, for consumers (eg, analytics queries) that can't
+// parse version strings themselves, eg:
+//
+//	{"raw":"v24.1.0-rc.1","year":24,"ordinal":1,"patch":0,"phase":"rc","phaseOrdinal":1}
+//
+// Fields other than raw, year, ordinal, and patch are omitted when they're
+// zero or empty; the phase is omitted for stable and adhoc versions. The raw
+// string remains authoritative: UnmarshalJSON reads the verbose form by
+// parsing raw, ignoring the other fields.
+func (v Version) MarshalJSONVerbose() ([]byte, error) {
+	if err := v.checkMarshalable(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(verboseJSON{
+		Raw:             v.raw,
+		Year:            v.year,
+		Ordinal:         v.ordinal,
+		Patch:           v.patch,
+		Phase:           phaseNames[v.phase],
+		PhaseOrdinal:    v.phaseOrdinal,
+		PhaseSubOrdinal: v.phaseSubOrdinal,
+		CustomOrdinal:   v.customOrdinal,
+		AdhocLabel:      v.adhocLabel,
+		Incompat:        v.incompat,
+		FIPS:            v.fips,
+		BuildMetadata:   v.buildMetadata,
+	})
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler]. In addition to the
+// {"$raw": "..."} form written by MarshalJSON, UnmarshalJSON accepts a plain
+// JSON string, which is how versions were stored before the envelope existed,
+// and the verbose form written by [Version.MarshalJSONVerbose].
 func (v *Version) UnmarshalJSON(data []byte) error {
-	var rawMap map[string]string
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		parsed, err := Parse(str)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+		return nil
+	}
+
+	var rawMap map[string]json.RawMessage
 	if err := json.Unmarshal(data, &rawMap); err != nil {
 		return err
 	}
-	if rawValue, ok := rawMap["$raw"]; ok {
-		parsed, err := Parse(rawValue)
+	rawValue, ok := rawMap["$raw"]
+	if !ok {
+		// the verbose form; the other fields are derived from raw
+		rawValue, ok = rawMap["raw"]
+	}
+	if ok {
+		var str string
+		if err := json.Unmarshal(rawValue, &str); err != nil {
+			return err
+		}
+		parsed, err := Parse(str)
 		if err != nil {
 			return err
 		}
@@ -168,7 +491,27 @@ func (v *Version) UnmarshalJSON(data []byte) error {
 func (v Version) IsPrerelease() bool {
 	// cloudonly phase *is* stable, it's just not available to SH
 	// customers, and has a special version suffix inside of CC
-	return v.phase < cloudonly && !v.Empty()
+	return v.phase < CloudOnly && !v.Empty() && !v.latest
+}
+
+// IsAlpha determines whether the version is an alpha pre-release, eg "v24.1.0-alpha.1".
+func (v Version) IsAlpha() bool {
+	return v.phase == Alpha
+}
+
+// IsBeta determines whether the version is a beta pre-release, eg "v24.1.0-beta.1".
+func (v Version) IsBeta() bool {
+	return v.phase == Beta
+}
+
+// IsRC determines whether the version is a release candidate, eg "v24.1.0-rc.1".
+func (v Version) IsRC() bool {
+	return v.phase == RC
+}
+
+// IsReleaseCandidate is an alias for [Version.IsRC].
+func (v Version) IsReleaseCandidate() bool {
+	return v.IsRC()
 }
 
 // IsCustomOrAdhocBuild determines if the version is a adhoc build or adhoc build.
@@ -181,6 +524,22 @@ func (v Version) IsCustomBuild() bool {
 	return v.customOrdinal > 0
 }
 
+// CommitsSinceTag returns the number of commits past the tagged release for
+// builds described by `git describe` (eg, 12 for "v24.1.0-12-gabcdef1"), and
+// whether the version is such a build. Other adhoc builds, like those with
+// arbitrary labels ("v24.1.0-foo") or image digests ("sha256:...:latest-v24.1-build"),
+// return (0, false).
+func (v Version) CommitsSinceTag() (int, bool) {
+	return v.customOrdinal, v.IsCustomBuild()
+}
+
+// CommitsSinceRelease is the same as [Version.CommitsSinceTag]: it returns the
+// number of commits past the release that a `git describe` build (eg, 37 for
+// "v24.1.0-37-gabcdef1") was built from, and whether v is such a build.
+func (v Version) CommitsSinceRelease() (int, bool) {
+	return v.CommitsSinceTag()
+}
+
 // IsAdhocBuild determines if the version is a adhoc build.
 func (v Version) IsAdhocBuild() bool {
 	return v.adhocLabel != ""
@@ -188,7 +547,43 @@ func (v Version) IsAdhocBuild() bool {
 
 // IsCloudOnlyBuild determines if the version is a CockroachDB Cloud specific build.
 func (v Version) IsCloudOnlyBuild() bool {
-	return v.phase == cloudonly
+	return v.phase == CloudOnly
+}
+
+// IsFIPS determines if the version is a FIPS-compliant build, marked with a
+// "-fips" suffix, eg "v24.1.3-fips". FIPS builds sort just after their
+// non-FIPS counterparts (see [Version.Compare]).
+func (v Version) IsFIPS() bool {
+	return v.fips
+}
+
+// IsSameBuildExceptFIPS returns true if v and w are the same build, except
+// perhaps for whether they're FIPS builds, eg "v24.1.3-fips" and "v24.1.3".
+// Unlike [Version.Equals], build metadata must match, too. Two non-FIPS (or
+// two FIPS) builds of the same version are trivially the same build.
+func (v Version) IsSameBuildExceptFIPS(w Version) bool {
+	v.fips, w.fips = false, false
+	return v.Compare(w) == 0 && v.buildMetadata == w.buildMetadata
+}
+
+// IsSelfHostedAvailable determines if the version is (or is built from) a
+// release that self-hosted customers can download. The rule is that the
+// version's phase is stable or adhoc: stable releases ("v24.1.0") and builds
+// on top of them ("v24.1.0-12-gabcdef1", "v24.1.0-custom-label") are available,
+// while pre-releases (alpha, beta, and rc, including builds on top of them)
+// and cloudonly releases, which are stable but only shipped to CockroachDB
+// Cloud, are not. The zero Version is not available.
+func (v Version) IsSelfHostedAvailable() bool {
+	return (v.phase == Stable || v.phase == Adhoc) && !v.Empty()
+}
+
+// BreaksWireCompat determines if the version is a build that intentionally
+// breaks mixed-version (wire) compatibility. Such builds are marked with a
+// trailing "-incompat", which may follow any other version form, eg
+// "v24.1.0-incompat" or "v24.1.0-rc.1-12-gabcdef1-incompat". The marker sorts
+// after an otherwise-equal unmarked version.
+func (v Version) BreaksWireCompat() bool {
+	return v.incompat
 }
 
 // String returns the original version string passed to [Parse].
@@ -196,6 +591,104 @@ func (v Version) String() string {
 	return redact.StringWithoutMarkers(v)
 }
 
+// ArtifactStem returns the version as it appears in the names of release
+// artifacts, like the "v24.1.3-fips" in "cockroach-v24.1.3-fips.linux-amd64".
+// It's the original version string, including any pre-release, adhoc, -fips,
+// and +metadata suffixes, with characters other than letters, digits, ".",
+// "-", "_", and "+" replaced by "-", so that it's safe to embed in a file
+// name. Only the "sha256:<hash>:latest-vX.Y-build" form has such characters.
+func (v Version) ArtifactStem() string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		case r == '.', r == '-', r == '_', r == '+':
+			return r
+		default:
+			return '-'
+		}
+	}, v.raw)
+}
+
+// maxLabelValueLength is the longest allowed Kubernetes label value.
+const maxLabelValueLength = 63
+
+// labelValueSHARE matches the git SHA of a `git describe` build, capturing
+// its first 10 characters, which are all that LabelValue keeps.
+var labelValueSHARE = regexp.MustCompile(`(-[0-9]+-g[a-f0-9]{10})[a-f0-9]+`)
+
+// redactedSHARE matches the commit count and git SHA of a `git describe`
+// build, which Redacted removes.
+var redactedSHARE = regexp.MustCompile(`-[0-9]+-g[0-9a-f]+\b`)
+
+// LabelValue returns the version in a form that's valid as a Kubernetes label
+// value: at most 63 characters from [-A-Za-z0-9_.], starting and ending with a
+// letter or digit. The "vX.Y.Z" and any pre-release phase are always kept, but
+// the conversion is lossy:
+//
+// - "+" (before build metadata) and other disallowed characters become "_"
+// - git SHAs in `git describe` builds are abbreviated to 10 characters
+// - "sha256:<hash>:latest-vX.Y-build" becomes "latest-vX.Y-build_<hash>",
+// with the hash abbreviated to 12 characters
+// - whatever remains is truncated to 63 characters
+//
+// The result is meant for labeling and selecting, not for parsing back into a
+// version.
+func (v Version) LabelValue() string {
+	str := v.raw
+	if hash, ok := strings.CutPrefix(v.raw, "sha256:"); ok {
+		hash, _, _ = strings.Cut(hash, ":")
+		str = v.Format("latest-v%X.%Y-build_") + hash[:min(len(hash), 12)]
+	}
+	str = labelValueSHARE.ReplaceAllString(str, "$1")
+	str = strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		case r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, str)
+	if len(str) > maxLabelValueLength {
+		str = str[:maxLabelValueLength]
+	}
+	return strings.TrimRight(str, "-_.")
+}
+
+// GoString implements [fmt.GoStringer], so that the %#v verb prints a Go
+// expression that evaluates to v, eg `version.MustParse("v24.1.0")`. This
+// makes test failure messages involving versions easier to act on.
+func (v Version) GoString() string {
+	if v.latest {
+		return "version.Latest"
+	}
+	if v.raw == "" {
+		return "version.Version{}"
+	}
+	return fmt.Sprintf("version.MustParse(%q)", v.raw)
+}
+
+// Redacted returns the version string with build provenance removed, for
+// sharing outside of Cockroach Labs (eg, in a public issue). Unlike the
+// [redact] integration, which marks sensitive parts of a string but keeps
+// them, Redacted strips them eagerly:
+//
+// - the commit count and git SHA of adhoc builds are removed, so
+// "v24.1.0-rc.1-12-gabcdef1-fips" becomes "v24.1.0-rc.1-fips"
+// - the digest of image builds is removed, so
+// "sha256:<hash>:latest-v24.1-build" becomes "latest-v24.1-build"
+//
+// Other versions are returned as-is.
+func (v Version) Redacted() string {
+	str := v.raw
+	if strings.HasPrefix(str, "sha256:") {
+		str = str[strings.LastIndex(str, ":")+1:]
+	}
+	return redactedSHARE.ReplaceAllString(str, "")
+}
+
 // SafeFormat implements [redact.SafePrinter].
 func (v Version) SafeFormat(p redact.SafePrinter, _ rune) {
 	p.Print(v.raw)
@@ -203,30 +696,113 @@ func (v Version) SafeFormat(p redact.SafePrinter, _ rune) {
 
 // Parse creates a version from a string.
 func Parse(str string) (Version, error) {
-	// these are roughly in "how often we expect to see them" order
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))(?:-fips)?$`),
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>alpha|beta|rc|cloudonly)\.(?P<phaseOrdinal>[0-9]+)(?:-fips)?$`),
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<customOrdinal>(?:[1-9][0-9]*|0))-g[a-f0-9]+(?:-fips)?$`),
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>alpha|beta|rc|cloudonly).(?P<phaseOrdinal>[0-9]+)-(?P<customOrdinal>(?:[1-9][0-9]*|0))-g[a-f0-9]+(?:-fips)?$`),
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>alpha|beta|rc|cloudonly).(?P<phaseOrdinal>[0-9]+)-cloudonly(-rc|\.)(?P<phaseSubOrdinal>(?:[1-9][0-9]*|0))$`),
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>cloudonly)-rc(?P<phaseOrdinal>[0-9]+)$`),
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>cloudonly)(?P<phaseOrdinal>[0-9]+)?$`),
+	return parse(str, false)
+}
 
-		// vX.Y.Z-<anything> will sort after the corresponding "plain" vX.Y.Z version
-		regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<adhocLabel>[-a-zA-Z0-9\.\+]+)$`),
+// ParseStrict is like Parse, but only accepts well-formed release versions,
+// rejecting version strings that are almost certainly malformed even though
+// Parse accepts them. Specifically:
+//
+// - adhoc labels (eg "v24.1.0-rcx" or "v23.1.0-swenson-mr-4") and image
+// digests ("sha256:<hash>:latest-vX.Y-build") are rejected; only the stable,
+// alpha, beta, rc, cloudonly, and `git describe` shapes are accepted
+// - `git describe` builds (eg "v24.1.0-12-gabcdef1") must carry a git SHA of
+// 7 to 40 hex characters
+func ParseStrict(str string) (Version, error) {
+	return parse(str, true)
+}
 
-		// sha256:<hash>:latest-vX.Y-build will sort just after vX.Y.0, but before vX.Y.1
-		regexp.MustCompile(`^sha256:(?P<adhocLabel>[^:]+):latest-v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)-build$`),
+// ParseBounded is like Parse, but also rejects versions whose year is outside
+// [minYear, maxYear], to catch typos like "v240.1.0" in user input. The error
+// for such a version wraps [ErrYearOutOfRange].
+func ParseBounded(str string, minYear, maxYear int) (Version, error) {
+	v, err := Parse(str)
+	if err != nil {
+		return Version{}, err
+	}
+	if v.year < minYear || v.year > maxYear {
+		return Version{}, errors.Wrapf(ErrYearOutOfRange, "invalid version string '%s': year %d is not between %d and %d",
+			str, v.year, minYear, maxYear)
 	}
+	return v, nil
+}
+
+// parsePatterns are the version string shapes recognized by [Parse]. They're
+// roughly in "how often we expect to see them" order.
+var parsePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))(?P<fips>-fips)?$`),
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>[a-z]+)\.(?P<phaseOrdinal>[0-9]+)(?P<fips>-fips)?$`),
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<customOrdinal>(?:[1-9][0-9]*|0))-g(?P<sha>[a-f0-9]+)(?P<fips>-fips)?$`),
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>[a-z]+).(?P<phaseOrdinal>[0-9]+)-(?P<customOrdinal>(?:[1-9][0-9]*|0))-g(?P<sha>[a-f0-9]+)(?P<fips>-fips)?$`),
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>[a-z]+).(?P<phaseOrdinal>[0-9]+)-cloudonly(-rc|\.)(?P<phaseSubOrdinal>(?:[1-9][0-9]*|0))$`),
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>cloudonly)-rc(?P<phaseOrdinal>[0-9]+)$`),
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<phase>cloudonly)(?P<phaseOrdinal>[0-9]+)?$`),
+
+	// vX.Y.Z-<anything> will sort after the corresponding "plain" vX.Y.Z version
+	regexp.MustCompile(`^v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)\.(?P<patch>(?:[1-9][0-9]*|0))-(?P<adhocLabel>[-a-zA-Z0-9\.\+]+)$`),
+
+	// sha256:<hash>:latest-vX.Y-build will sort just after vX.Y.0, but before vX.Y.1
+	regexp.MustCompile(`^sha256:(?P<adhocLabel>[^:]+):latest-v(?P<year>[1-9][0-9]*)\.(?P<ordinal>[1-9][0-9]*)-build$`),
+}
+
+// preReleasePhases maps phase names, as they appear in parsePatterns, to
+// phases. Besides the built-in names, it includes aliases registered with
+// [RegisterPhaseAlias], so it's guarded by preReleasePhasesMu.
+var preReleasePhases = map[string]Phase{
+	"alpha":     Alpha,
+	"beta":      Beta,
+	"rc":        RC,
+	"cloudonly": CloudOnly,
+}
+
+var preReleasePhasesMu sync.RWMutex
+
+// lookupPhase returns the phase named name, if any.
+func lookupPhase(name string) (Phase, bool) {
+	preReleasePhasesMu.RLock()
+	defer preReleasePhasesMu.RUnlock()
+	p, ok := preReleasePhases[name]
+	return p, ok
+}
+
+// phaseAliasRE matches the phase aliases accepted by RegisterPhaseAlias.
+var phaseAliasRE = regexp.MustCompile(`^[a-z]+$`)
 
-	preReleasePhase := map[string]releasePhase{
-		"alpha":     alpha,
-		"beta":      beta,
-		"rc":        rc,
-		"cloudonly": cloudonly,
+// RegisterPhaseAlias registers alias as another name for the pre-release
+// phase p, eg "pre" or "preview" for [RC], so that [Parse] accepts versions
+// like "v24.1.0-pre.1" and treats them as equal to "v24.1.0-rc.1". Aliases
+// may be used wherever a phase name is expected in a version string, except
+// in the "-cloudonly" suffix forms. Versions keep their original string, but
+// [Version.Normalize] rewrites aliases to the built-in phase name.
+//
+// Registration affects all parsing in the process, including strings that
+// would otherwise be parsed as adhoc labels (eg, without an alias,
+// "v24.1.0-pre.1" is an adhoc version that sorts after v24.1.0). It's intended
+// to be called from an init function, but is safe to call concurrently with
+// parsing.
+//
+// RegisterPhaseAlias panics if alias isn't made up of lowercase letters, if p
+// isn't one of Alpha, Beta, RC, or CloudOnly, or if alias is already the name
+// of a different phase.
+func RegisterPhaseAlias(alias string, p Phase) {
+	if !phaseAliasRE.MatchString(alias) {
+		panic(fmt.Sprintf("invalid phase alias %q: must be lowercase letters", alias))
+	}
+	if p < Alpha || p > CloudOnly {
+		panic(fmt.Sprintf("invalid phase alias %q: phase %d is not a pre-release phase", alias, p))
+	}
+	preReleasePhasesMu.Lock()
+	defer preReleasePhasesMu.Unlock()
+	if existing, ok := preReleasePhases[alias]; ok && existing != p {
+		panic(fmt.Sprintf("invalid phase alias %q: already the name of phase %s", alias, phaseNames[existing]))
 	}
+	preReleasePhases[alias] = p
+}
+
+// buildMetadataRE matches the build metadata following a "+" in a version.
+var buildMetadataRE = regexp.MustCompile(`^[0-9A-Za-z\-\.]+$`)
 
+func parse(str string, strict bool) (Version, error) {
 	submatch := func(pat *regexp.Regexp, matches []string, group string) string {
 		index := pat.SubexpIndex(group)
 		if index == -1 {
@@ -235,57 +811,324 @@ func Parse(str string) (Version, error) {
 		return matches[index]
 	}
 
-	v := Version{raw: str, phase: stable}
+	v := Version{raw: str, phase: Stable}
+
+	// numeric groups are all digits, but may still be too large for an int
+	var rangeErr error
+	atoi := func(s string) int {
+		n, err := strconv.Atoi(s)
+		if err != nil && rangeErr == nil {
+			rangeErr = errors.Newf("invalid version string '%s': %s is out of range", v.raw, s)
+		}
+		return n
+	}
+
+	// semver-style build metadata, eg "+enterprise", follows everything else.
+	// Historical adhoc labels may contain "+" too (eg "v24.1.0-foo+a"), and
+	// keep their meaning: a "+" only starts build metadata if what precedes it
+	// is a version other than an adhoc build.
+	if base, metadata, ok := strings.Cut(str, "+"); ok {
+		if bv, err := parse(base, strict); err == nil && bv.phase != Adhoc {
+			if !buildMetadataRE.MatchString(metadata) {
+				return Version{}, errors.Newf("invalid version string '%s': build metadata '%s' must be non-empty and contain only [0-9A-Za-z-.]", v.raw, metadata)
+			}
+			bv.buildMetadata, bv.raw = metadata, v.raw
+			return bv, nil
+		}
+	}
 
-	for _, pat := range patterns {
-		if pat.MatchString(str) {
-			matches := pat.FindStringSubmatch(str)
+	// builds that intentionally break mixed-version compatibility carry a
+	// trailing -incompat, which may follow any of the other forms
+	str, v.incompat = strings.CutSuffix(str, "-incompat")
+
+	for _, pat := range parsePatterns {
+		if matches := pat.FindStringSubmatch(str); matches != nil {
+			// phases are matched as any lowercase word, so that aliases can be
+			// registered; words that aren't phases may still match a later
+			// pattern, such as an adhoc label
+			phaseWord := submatch(pat, matches, "phase")
+			phase, isPhase := lookupPhase(phaseWord)
+			if phaseWord != "" && !isPhase {
+				continue
+			}
 
 			// all patterns have vX.Y
-			v.year, _ = strconv.Atoi(submatch(pat, matches, "year"))
-			v.ordinal, _ = strconv.Atoi(submatch(pat, matches, "ordinal"))
+			v.year = atoi(submatch(pat, matches, "year"))
+			v.ordinal = atoi(submatch(pat, matches, "ordinal"))
 
-			// most have vX.Y.Z
+			// most have vX.Y.Z; the sha256:...:latest-vX.Y-build form doesn't, and
+			// keeps a patch of 0 so that it sorts among the vX.Y.0 builds
 			if patch := submatch(pat, matches, "patch"); patch != "" {
-				v.patch, _ = strconv.Atoi(patch)
+				v.patch = atoi(patch)
 			}
 
 			// handle -alpha.1, -rc.3, etc
-			if phase := submatch(pat, matches, "phase"); phase != "" {
-				if phaseName, ok := preReleasePhase[phase]; !ok {
-					return Version{}, errors.Newf("unknown phase '%s", phaseName)
-				} else {
-					v.phase = phaseName
-				}
+			if isPhase {
+				v.phase = phase
 
 				if ord := submatch(pat, matches, "phaseOrdinal"); ord != "" {
-					v.phaseOrdinal, _ = strconv.Atoi(ord)
+					v.phaseOrdinal = atoi(ord)
 				}
 				// -beta.1-cloudonly-rc1
 				if subOrd := submatch(pat, matches, "phaseSubOrdinal"); subOrd != "" {
-					v.phaseSubOrdinal, _ = strconv.Atoi(subOrd)
+					v.phaseSubOrdinal = atoi(subOrd)
 				}
 			}
 
+			v.fips = submatch(pat, matches, "fips") != ""
+
 			// adhoc/adhoc builds, eg -10-g7890abcd
 			if ord := submatch(pat, matches, "customOrdinal"); ord != "" {
-				v.customOrdinal, _ = strconv.Atoi(ord)
+				v.customOrdinal = atoi(ord)
+			}
+			if sha := submatch(pat, matches, "sha"); strict && sha != "" {
+				if len(sha) < 7 || len(sha) > 40 {
+					return Version{}, errors.Newf("invalid version string '%s': git SHA '%s' must be 7-40 hex characters", v.raw, sha)
+				}
 			}
 
 			// arbitrary/adhoc build tags; we have these old versions and need to parse them
 			if adhocLabel := submatch(pat, matches, "adhocLabel"); adhocLabel != "" {
-				v.phase = adhoc
+				if strict {
+					return Version{}, errors.Newf("invalid version string '%s': adhoc label '%s' is not allowed", v.raw, adhocLabel)
+				}
+				v.phase = Adhoc
 				v.adhocLabel = adhocLabel
 			}
 
+			if rangeErr != nil {
+				return Version{}, rangeErr
+			}
 			return v, nil
 		}
 	}
 
-	err := errors.Errorf("invalid version string '%s'", str)
+	if err := diagnose(v.raw); err != nil {
+		return Version{}, err
+	}
+	err := errors.Errorf("invalid version string '%s'", v.raw)
 	return Version{}, err
 }
 
+// ParseImageTag creates a version from a container image reference, like
+// "cockroachdb/cockroach:v24.1.3" or "us-docker.pkg.dev/project/repo/cockroach:v24.1.3",
+// by parsing its tag as a version. The tag follows the last ":" in the image
+// name (after the last "/", so a registry port like "localhost:5000" isn't
+// taken for a tag), and a trailing "@sha256:<hash>" digest is ignored. The
+// special "sha256:<hash>:latest-vX.Y-build" form is parsed as a whole.
+func ParseImageTag(str string) (Version, error) {
+	if strings.HasPrefix(str, "sha256:") {
+		return Parse(str)
+	}
+	ref, digest, ok := strings.Cut(str, "@")
+	if ok && digest == "" {
+		return Version{}, errors.Newf("image reference '%s' has an empty digest", str)
+	}
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	if i == -1 {
+		return Version{}, errors.Newf("image reference '%s' has no tag", str)
+	}
+	v, err := Parse(name[i+1:])
+	if err != nil {
+		return Version{}, errors.Wrapf(err, "image reference '%s' is not tagged with a version", str)
+	}
+	return v, nil
+}
+
+// ParseBuildOutput creates a version from the output of the `cockroach version`
+// command, which includes a line like "Build Tag:        v24.1.3". The version
+// on the first such line is parsed; other lines are ignored, as is whitespace
+// around the tag and the output's line endings.
+func ParseBuildOutput(output string) (Version, error) {
+	for _, line := range strings.Split(output, "\n") {
+		tag, ok := strings.CutPrefix(strings.TrimSpace(line), "Build Tag:")
+		if !ok {
+			continue
+		}
+		v, err := Parse(strings.TrimSpace(tag))
+		if err != nil {
+			return Version{}, errors.Wrap(err, "invalid Build Tag in cockroach version output")
+		}
+		return v, nil
+	}
+	return Version{}, errors.New("no Build Tag line in cockroach version output")
+}
+
+// versionPrefixRE matches the strings that are the start of a version, as
+// accepted by ParsePrefix.
+var versionPrefixRE = regexp.MustCompile(`^(?:v(?:[1-9][0-9]*(?:\.(?:[1-9][0-9]*(?:\.(?:(?:0|[1-9][0-9]*)(?:-[-a-zA-Z0-9.+]*)?(?:\+[-a-zA-Z0-9.]*)?)?)?)?)?)?)?$`)
+
+// ParsePrefix reports on a partially-typed version string, such as input to an
+// interactive prompt. A string is in one of three states:
+//
+// - complete (and valid): the string is a version accepted by [Parse]. More
+// input may or may not keep it complete; eg, "v24.1.0" may become "v24.1.0-rc.1"
+// or "v24.1.01".
+// - valid but incomplete: the string isn't a version, but is the start of one,
+// eg "", "v", "v24.", "v24.1", "v24.1.0-", or "v24.1.0+".
+// - invalid: no amount of additional input will make the string a version,
+// eg "24.1" or "v24.01".
+//
+// The "sha256:<hash>:latest-vX.Y-build" form is only recognized once complete.
+func ParsePrefix(s string) (complete bool, valid bool) {
+	if _, err := Parse(s); err == nil {
+		return true, true
+	}
+	return false, versionPrefixRE.MatchString(s)
+}
+
+// bestEffortSeriesRE matches the release series ("vX.Y") at the start of a
+// malformed version string.
+var bestEffortSeriesRE = regexp.MustCompile(`^v([1-9][0-9]*)\.([1-9][0-9]*)(?:[^0-9]|$)`)
+
+// ParseBestEffort is like Parse, but salvages what it can from malformed
+// version strings, for callers such as metrics pipelines that would rather
+// keep an approximate version than none at all. If str is a valid version,
+// it's returned along with true. Otherwise, if str starts with a release
+// series ("vX.Y"), ParseBestEffort returns false along with the first release
+// of that series, eg "v24.1.0" for "v24.1.x-garbage", whose String parses
+// back to it. Otherwise, ParseBestEffort returns the zero Version and false.
+func ParseBestEffort(str string) (Version, bool) {
+	if v, err := Parse(str); err == nil {
+		return v, true
+	}
+	groups := bestEffortSeriesRE.FindStringSubmatch(str)
+	if groups == nil {
+		return Version{}, false
+	}
+	var m MajorVersion
+	m.Year, _ = strconv.Atoi(groups[1])
+	m.Ordinal, _ = strconv.Atoi(groups[2])
+	return m.FirstRelease(), false
+}
+
+// lenientWordRE matches the words ParseLenient lowercases if they're phases.
+var lenientWordRE = regexp.MustCompile(`-[a-zA-Z]+`)
+
+// ParseLenient is like Parse, but accepts phase names in any case, eg
+// "v24.1.0-RC.1" or "v24.1.0-Beta.2", which Parse accepts only as adhoc
+// labels. Phase names are lowercased in the returned version, so its String is
+// "v24.1.0-rc.1". Strings that aren't a valid version once their phase names
+// are lowercased are parsed as-is.
+func ParseLenient(str string) (Version, error) {
+	core, metadata, hasMetadata := strings.Cut(str, "+")
+	lowered := lenientWordRE.ReplaceAllStringFunc(core, func(word string) string {
+		if _, ok := lookupPhase(strings.ToLower(word[1:])); ok {
+			return strings.ToLower(word)
+		}
+		return word
+	})
+	if hasMetadata {
+		lowered += "+" + metadata
+	}
+	if lowered != str {
+		if v, err := Parse(lowered); err == nil && v.phase != Adhoc {
+			return v, nil
+		}
+	}
+	return Parse(str)
+}
+
+// Latest is the symbolic version "latest", which stands for the newest
+// available version; see [ParseSymbolic]. Its String is "latest".
+//
+// Latest only has a place in the ordering of versions: it compares greater
+// than every concrete version, and equal only to itself, so it can be sorted
+// along with them. It has no series, patch, or phase, so the methods that
+// return these, or versions derived from them, panic, and methods that return
+// an error reject it. It also can't be marshaled. Resolve it to a concrete
+// version (eg, with [ResolveLatest]) first.
+var Latest = Version{latest: true, raw: "latest"}
+
+// ParseSymbolic is like Parse, but also accepts the symbolic version "latest",
+// for which it returns [Latest] and true. For any other string, it returns the
+// result of Parse and false.
+func ParseSymbolic(str string) (Version, bool, error) {
+	if str == Latest.raw {
+		return Latest, true, nil
+	}
+	v, err := Parse(str)
+	return v, false, err
+}
+
+// IsLatest returns true if v is the symbolic version [Latest].
+func (v Version) IsLatest() bool {
+	return v.latest
+}
+
+// checkNotLatest panics if v is the symbolic [Latest] version, which has no
+// fields to read.
+func (v Version) checkNotLatest() {
+	if v.latest {
+		panic("the symbolic version latest has no fields; resolve it to a concrete version first")
+	}
+}
+
+// checkMarshalable returns an error if v is the symbolic [Latest] version,
+// which isn't marshaled, since it can't be parsed back.
+func (v Version) checkMarshalable() error {
+	if v.latest {
+		return errors.New("cannot marshal the symbolic version latest; resolve it to a concrete version first")
+	}
+	return nil
+}
+
+// ParseOrZero is like Parse, but returns the zero Version (and no error) for
+// an empty or all-whitespace string, which is how [Version.Scan] treats an
+// empty string. It's intended for optional configuration fields, where a blank
+// value means "no version". Other strings, including versions with leading or
+// trailing whitespace, are passed to Parse as-is.
+func ParseOrZero(s string) (Version, error) {
+	if strings.TrimSpace(s) == "" {
+		return Version{}, nil
+	}
+	return Parse(s)
+}
+
+// ParseOrDefault is like Parse, but returns def if str is not a valid
+// version, for configuration with a fallback value. Unlike [MustParse], it
+// never panics.
+func ParseOrDefault(str string, def Version) Version {
+	v, err := Parse(str)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// ParseTrimmed is like Parse, but ignores leading and trailing whitespace
+// (spaces, tabs, newlines, and so on), which often surrounds versions read
+// from YAML scalars or CSV cells. The returned version's String doesn't include
+// the whitespace. Parse itself rejects padded strings.
+func ParseTrimmed(s string) (Version, error) {
+	return Parse(strings.TrimSpace(s))
+}
+
+// AdhocVersion returns the version of a build made commits commits past the
+// stable release base, at the git commit sha, as described by `git describe`:
+// "vX.Y.Z-<commits>-g<sha>" (eg, "v24.1.0-12-gabcdef1"). A "-fips" suffix on
+// base is preserved. This method returns an error if base is not a stable
+// release, if commits is not positive, or if sha is not 7 to 40 lowercase hex
+// characters (see [ParseStrict]).
+func AdhocVersion(base Version, commits int, sha string) (Version, error) {
+	if base.phase != Stable || base.IsCustomBuild() || base.Empty() {
+		return Version{}, errors.Newf("base version %s is not a stable release", base.String())
+	}
+	if commits <= 0 {
+		return Version{}, errors.Newf("commit count %d must be positive", commits)
+	}
+	str := base.Format("v%X.%Y.%Z") + fmt.Sprintf("-%d-g%s", commits, sha)
+	if base.fips {
+		str += "-fips"
+	}
+	v, err := ParseStrict(str)
+	if err != nil {
+		return Version{}, errors.Wrapf(err, "invalid git SHA '%s'", sha)
+	}
+	return v, nil
+}
+
 // MustParse is like Parse but panics on any error. Recommended as an
 // initializer for global values.
 func MustParse(str string) Version {
@@ -306,6 +1149,19 @@ func MustParse(str string) Version {
 // rc, cloudonly. Pre-release versions will look like "v24.1.0-cloudonly.1"
 // or "v23.2.0-rc.1".
 //
+// Cloudonly versions have several historical spellings. "-cloudonly.N",
+// "-cloudonly-rcN", and "-cloudonlyN" are the same version and compare equal,
+// as do the sub-ordinal forms "-rc.1-cloudonly.N" and "-rc.1-cloudonly-rcN".
+// At the same base version the order is:
+//
+//	v24.1.0-rc.1
+//	v24.1.0-rc.1-cloudonly.1   (== -rc.1-cloudonly-rc1)
+//	v24.1.0-rc.2
+//	v24.1.0-cloudonly          (no ordinal, so 0)
+//	v24.1.0-cloudonly.1        (== -cloudonly-rc1 == -cloudonly1)
+//	v24.1.0-cloudonly.2
+//	v24.1.0
+//
 // Additionally, we have adhoc builds, which have suffixes like "-<n>-g<hex>",
 // where <n> is an integer commit count past the branch point, and <hex> is
 // the git SHA. These versions sort AFTER the corresponding "normal" version,
@@ -314,38 +1170,197 @@ func MustParse(str string) Version {
 // A version can have both a pre-release and adhoc build suffix, like
 // "v24.1.0-rc.2-14-g<hex>". In these cases, the pre-release portion has precedence,
 // so this example would sort after v24.1.0-rc.2, but before v24.1.0-rc.3.
+//
+// Builds which are otherwise equal are ordered by their flags: a build marked
+// "-incompat" sorts after an unmarked one, and then a "-fips" build sorts
+// after a non-FIPS one. At the same base version the order is:
+//
+//	v24.1.3
+//	v24.1.3-fips
+//	v24.1.3-incompat
+//	v24.1.3-fips-incompat
+//
+// The symbolic version [Latest] sorts after all other versions.
 func (v Version) Compare(w Version) int {
-	if rslt := cmp.Compare(v.year, w.year); rslt != 0 {
-		return rslt
+	// This is equivalent to v.Diff(w), but Compare is called in tight sorting
+	// loops, so it compares the integer fields (in the same reference order as
+	// compareFields) directly, without the indirection of compareFields.
+	if v.latest != w.latest {
+		return compareBool(v.latest, w.latest)
 	}
-	if rslt := cmp.Compare(v.ordinal, w.ordinal); rslt != 0 {
-		return rslt
+	vs := [...]int{v.year, v.ordinal, v.patch, int(v.phase), v.phaseOrdinal, v.phaseSubOrdinal, v.customOrdinal}
+	ws := [...]int{w.year, w.ordinal, w.patch, int(w.phase), w.phaseOrdinal, w.phaseSubOrdinal, w.customOrdinal}
+	for i := range vs {
+		if rslt := cmp.Compare(vs[i], ws[i]); rslt != 0 {
+			return rslt
+		}
 	}
-	if rslt := cmp.Compare(v.patch, w.patch); rslt != 0 {
+	if rslt := compareAdhocLabels(v.adhocLabel, w.adhocLabel); rslt != 0 {
 		return rslt
 	}
-	if rslt := cmp.Compare(v.phase, w.phase); rslt != 0 {
+	if rslt := compareBool(v.incompat, w.incompat); rslt != 0 {
 		return rslt
 	}
-	if rslt := cmp.Compare(v.phaseOrdinal, w.phaseOrdinal); rslt != 0 {
-		return rslt
+	return compareBool(v.fips, w.fips)
+}
+
+// CompareCore is like Compare, but compares only the release a version
+// identifies: its year, ordinal, patch, phase, phase ordinal, and phase
+// sub-ordinal. Unlike Compare, it ignores the commit count of `git describe`
+// builds, adhoc labels, -incompat, and -fips, so "v24.1.0-rc.1-12-gabcdef1" and
+// "v24.1.0-rc.1" compare equal. Note that versions with an adhoc label (eg
+// "v24.1.0-foo") are in their own phase, so still sort after the
+// corresponding stable version, though their labels are not compared.
+func (v Version) CompareCore(w Version) int {
+	if v.latest != w.latest {
+		return compareBool(v.latest, w.latest)
 	}
-	if rslt := cmp.Compare(v.phaseSubOrdinal, w.phaseSubOrdinal); rslt != 0 {
-		return rslt
+	vs := [...]int{v.year, v.ordinal, v.patch, int(v.phase), v.phaseOrdinal, v.phaseSubOrdinal}
+	ws := [...]int{w.year, w.ordinal, w.patch, int(w.phase), w.phaseOrdinal, w.phaseSubOrdinal}
+	for i := range vs {
+		if rslt := cmp.Compare(vs[i], ws[i]); rslt != 0 {
+			return rslt
+		}
 	}
-	if rslt := cmp.Compare(v.customOrdinal, w.customOrdinal); rslt != 0 {
-		return rslt
+	return 0
+}
+
+// CompareSafe is like Compare, but returns an error instead of comparing
+// versions whose relative ordering may be misleading. Currently, these are the
+// zero Version, which doesn't represent a real release but sorts before all of
+// them, and the symbolic [Latest] version, which stands for whichever release
+// is newest, and so must be resolved (eg, with [ResolveLatest]) first.
+func (v Version) CompareSafe(w Version) (int, error) {
+	if v.Empty() || w.Empty() {
+		return 0, errors.New("cannot safely compare an empty version")
 	}
-	if rslt := cmp.Compare(v.adhocLabel, w.adhocLabel); rslt != 0 {
+	if v.latest || w.latest {
+		return 0, errors.New("cannot safely compare the symbolic version latest")
+	}
+	return v.Compare(w), nil
+}
+
+// compareFields are the fields considered by [Version.Compare], in the
+// reference order (see [Version]). Compare inlines these comparisons, and
+// must be kept in sync with them.
+var compareFields = []struct {
+	name    string
+	compare func(v, w Version) int
+}{
+	{"latest", func(v, w Version) int { return compareBool(v.latest, w.latest) }},
+	{"year", func(v, w Version) int { return cmp.Compare(v.year, w.year) }},
+	{"ordinal", func(v, w Version) int { return cmp.Compare(v.ordinal, w.ordinal) }},
+	{"patch", func(v, w Version) int { return cmp.Compare(v.patch, w.patch) }},
+	{"phase", func(v, w Version) int { return cmp.Compare(v.phase, w.phase) }},
+	{"phaseOrdinal", func(v, w Version) int { return cmp.Compare(v.phaseOrdinal, w.phaseOrdinal) }},
+	{"phaseSubOrdinal", func(v, w Version) int { return cmp.Compare(v.phaseSubOrdinal, w.phaseSubOrdinal) }},
+	{"customOrdinal", func(v, w Version) int { return cmp.Compare(v.customOrdinal, w.customOrdinal) }},
+	{"adhocLabel", func(v, w Version) int { return compareAdhocLabels(v.adhocLabel, w.adhocLabel) }},
+	{"incompat", func(v, w Version) int { return compareBool(v.incompat, w.incompat) }},
+	{"fips", func(v, w Version) int { return compareBool(v.fips, w.fips) }},
+}
+
+// compareAdhocLabels compares adhoc labels as dot-separated identifiers, much
+// like SemVer compares pre-release identifiers: purely numeric identifiers are
+// compared numerically (so "build.2" < "build.10"), other identifiers are
+// compared lexically, numeric identifiers sort before non-numeric ones, and a
+// label sorts before any longer label it is a prefix of. Labels that are still
+// equal (eg, "build.01" and "build.1") are compared lexically.
+func compareAdhocLabels(a, b string) int {
+	if a == b {
+		return 0
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		aNumeric, bNumeric := isNumeric(as[i]), isNumeric(bs[i])
+		var rslt int
+		switch {
+		case aNumeric && bNumeric:
+			// compare by length first, to avoid overflowing on huge numbers
+			an, bn := strings.TrimLeft(as[i], "0"), strings.TrimLeft(bs[i], "0")
+			if rslt = cmp.Compare(len(an), len(bn)); rslt == 0 {
+				rslt = cmp.Compare(an, bn)
+			}
+		case aNumeric:
+			rslt = -1
+		case bNumeric:
+			rslt = 1
+		default:
+			rslt = cmp.Compare(as[i], bs[i])
+		}
+		if rslt != 0 {
+			return rslt
+		}
+	}
+	if rslt := cmp.Compare(len(as), len(bs)); rslt != 0 {
 		return rslt
 	}
-	return 0
+	return cmp.Compare(a, b)
 }
 
+// isNumeric returns true if s is a non-empty string of decimal digits.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Diff reports the name of the earliest field (in the reference order used by
+// [Version.Compare]) in which v and w differ, along with the result of
+// comparing that field, ie -1 or +1. Equal versions return ("", 0).
+func (v Version) Diff(w Version) (field string, cmp int) {
+	for _, f := range compareFields {
+		if rslt := f.compare(v, w); rslt != 0 {
+			return f.name, rslt
+		}
+	}
+	return "", 0
+}
+
+// Equals returns true if v and w are the same version, using
+// [Version.Compare]. Differently-spelled strings for the same version, such as
+// "v24.1.0-cloudonly.1" and "v24.1.0-cloudonly-rc1", are equal; use
+// [Version.EqualsRaw] to tell them apart. A FIPS build isn't equal to the
+// corresponding non-FIPS build (eg "v24.1.3-fips" and "v24.1.3"); use
+// [Version.IsSameBuildExceptFIPS] to ignore the difference.
 func (v Version) Equals(w Version) bool {
 	return v.Compare(w) == 0
 }
 
+// SemanticallyEqual is the same as [Version.Equals]. Its name is a reminder
+// that versions must be compared with this package's methods, not with == or
+// [reflect.DeepEqual], which also compare the original version string (and
+// fields which aren't part of the ordering, like build metadata).
+func (v Version) SemanticallyEqual(w Version) bool {
+	return v.Equals(w)
+}
+
+// EqualsRaw returns true if v and w were created from exactly the same string.
+// Unlike [Version.Equals], which compares versions by meaning, EqualsRaw
+// distinguishes between different spellings of the same version, such as
+// "v24.1.0-cloudonly.1" and "v24.1.0-cloudonly-rc1".
+func (v Version) EqualsRaw(w Version) bool {
+	return v.raw == w.raw
+}
+
 func (v Version) LessThan(w Version) bool {
 	return v.Compare(w) < 0
 }
@@ -355,34 +1370,255 @@ func (v Version) Empty() bool {
 	return v.Equals(Version{})
 }
 
-// Convenience wrapper for v.Major.Compare(w.Major())
+// Convenience wrapper for v.Major.Compare(w.Major()).
 func (v Version) CompareSeries(w Version) int {
 	return v.Major().Compare(w.Major())
 }
 
+// HasReachedSeries returns true if v is in release series m or a later one.
+// Prereleases count as part of the series they lead up to, so
+// "v24.1.0-alpha.1" has reached v24.1.
+func (v Version) HasReachedSeries(m MajorVersion) bool {
+	return v.Major().AtLeast(m)
+}
+
+// SamePatch returns true if v and w have the same year, ordinal, and patch
+// (the "vX.Y.Z" part), regardless of any prerelease or build suffix, eg
+// "v24.1.0-rc.1" and "v24.1.0". Unlike [Version.CompareSeries], the patch is
+// considered; unlike [Version.Equals], suffixes are not.
+func (v Version) SamePatch(w Version) bool {
+	return v.Major() == w.Major() && v.Patch() == w.Patch()
+}
+
+// PatchDistance returns the number of patches from v to w, eg 3 from
+// "v24.1.2" to "v24.1.5", or -3 from "v24.1.5" to "v24.1.2". An error is
+// returned if v and w are in different release series, or if either isn't a
+// stable version, since the distance between pre-releases is ill-defined.
+func (v Version) PatchDistance(w Version) (int, error) {
+	if v.phase != Stable || w.phase != Stable {
+		return 0, errors.Newf("cannot compute the patch distance from %s to %s: both must be stable versions", v.String(), w.String())
+	}
+	if v.Major() != w.Major() {
+		return 0, errors.Newf("cannot compute the patch distance from %s to %s: they are in different release series", v.String(), w.String())
+	}
+	return w.patch - v.patch, nil
+}
+
+// IsImmediatePatchSuccessor returns true if w is the patch release right
+// after v in the same release series, eg "v24.1.3" after "v24.1.2". Both must
+// be stable versions; see [Version.PatchDistance].
+func (v Version) IsImmediatePatchSuccessor(w Version) bool {
+	d, err := v.PatchDistance(w)
+	return err == nil && d == 1
+}
+
 // AtLeast returns true if v >= w.
 func (v Version) AtLeast(w Version) bool {
 	return v.Compare(w) >= 0
 }
 
+// Between returns true if v lies between lo and hi, using [Version.Compare],
+// so the usual prerelease ordering applies; eg, "v24.1.0-rc.1" is in
+// [v24.1.0-rc.1, v24.1.0). loInclusive and hiInclusive control whether v may
+// equal lo or hi, respectively. If lo and hi are equal, only that version is
+// between them, and only if both bounds are inclusive.
+func (v Version) Between(lo, hi Version, loInclusive, hiInclusive bool) bool {
+	loCmp, hiCmp := v.Compare(lo), v.Compare(hi)
+	return (loCmp > 0 || (loInclusive && loCmp == 0)) &&
+		(hiCmp < 0 || (hiInclusive && hiCmp == 0))
+}
+
+// Max returns the greater of v and w. If they are equal, v is returned.
+func (v Version) Max(w Version) Version {
+	if w.Compare(v) > 0 {
+		return w
+	}
+	return v
+}
+
+// Min returns the lesser of v and w. If they are equal, v is returned.
+func (v Version) Min(w Version) Version {
+	if w.Compare(v) < 0 {
+		return w
+	}
+	return v
+}
+
+// WithoutPrerelease returns the stable "vX.Y.Z" version that v is a
+// pre-release or adhoc build of, by clearing every field other than year,
+// ordinal, and patch. For an already-stable version, an equal version is
+// returned.
+func (v Version) WithoutPrerelease() Version {
+	v.checkNotLatest()
+	base := Version{
+		phase:   Stable,
+		year:    v.year,
+		ordinal: v.ordinal,
+		patch:   v.patch,
+	}
+	base.raw = base.Format("v%X.%Y.%Z")
+	return base
+}
+
+// TargetRelease returns the stable release that v leads up to, eg "v24.1.0"
+// for "v24.1.0-rc.2", or "v24.1.3" for "v24.1.3-alpha.1". It's the same as
+// [Version.WithoutPrerelease], and is provided for callers for whom this name
+// better describes their intent; in particular, an already-stable version
+// targets an equal version (without its -fips or other suffixes).
+func (v Version) TargetRelease() Version {
+	return v.WithoutPrerelease()
+}
+
+// WithBuildMetadata returns v with its build metadata (see
+// [Version.BuildMetadata]) replaced by meta, eg "v24.1.3" with "enterprise" is
+// "v24.1.3+enterprise"; an empty meta removes any build metadata. The rest of
+// the version string is kept as-is, and since build metadata is ignored when
+// comparing versions, the result is equal to v. This method returns an error
+// if meta contains characters other than [0-9A-Za-z-.], or if the resulting
+// string wouldn't parse back to v with the new metadata, as for adhoc builds
+// (see [Version.BuildMetadata]), the zero Version, and the symbolic [Latest]
+// version.
+func (v Version) WithBuildMetadata(meta string) (Version, error) {
+	if meta != "" && !buildMetadataRE.MatchString(meta) {
+		return Version{}, errors.Newf("invalid build metadata '%s': must contain only [0-9A-Za-z-.]", meta)
+	}
+	str := v.raw
+	if v.buildMetadata != "" {
+		str = strings.TrimSuffix(str, "+"+v.buildMetadata)
+	}
+	if meta != "" {
+		str += "+" + meta
+	}
+	parsed, err := Parse(str)
+	if err != nil || !parsed.Equals(v) || parsed.buildMetadata != meta {
+		return Version{}, errors.Newf("cannot attach build metadata '%s' to version %s", meta, v.String())
+	}
+	return parsed, nil
+}
+
+// A Granularity is a level of detail to which [Version.Truncate] reduces a
+// version.
+type Granularity int
+
+const (
+	// Major is release series granularity, eg "v24.1".
+	Major = Granularity(iota + 1)
+	// Patch is patch release granularity, eg "v24.1.3".
+	Patch
+)
+
+// granularityNames are the names of each granularity, as used by
+// [Granularity.String] and text marshaling.
+var granularityNames = map[Granularity]string{
+	Major: "major",
+	Patch: "patch",
+}
+
+// String returns the granularity's name, "major" or "patch".
+func (g Granularity) String() string {
+	if name, ok := granularityNames[g]; ok {
+		return name
+	}
+	return fmt.Sprintf("Granularity(%d)", int(g))
+}
+
+// MarshalText implements [encoding.TextMarshaler], writing the granularity's
+// name. An error is returned for an undeclared granularity.
+func (g Granularity) MarshalText() ([]byte, error) {
+	name, ok := granularityNames[g]
+	if !ok {
+		return nil, errors.Newf("invalid granularity %d", int(g))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], accepting the names
+// returned by [Granularity.String].
+func (g *Granularity) UnmarshalText(text []byte) error {
+	for granularity, name := range granularityNames {
+		if name == string(text) {
+			*g = granularity
+			return nil
+		}
+	}
+	return errors.Newf("unknown granularity '%s'", text)
+}
+
+// Truncate returns the stable version at granularity level which v belongs
+// to, clearing all finer-grained fields: Truncate(Patch) returns the "vX.Y.Z"
+// release, dropping any pre-release or build suffix (eg, "v24.1.3" for
+// "v24.1.3-rc.1"), and Truncate(Major) returns the first release of the
+// series, "vX.Y.0". The zero Version is returned unchanged. Truncate panics
+// if level is not a known Granularity.
+func (v Version) Truncate(level Granularity) Version {
+	if v.Empty() {
+		return v
+	}
+	switch level {
+	case Major:
+		return v.Major().FirstRelease()
+	case Patch:
+		return v.WithoutPrerelease()
+	default:
+		panic(fmt.Sprintf("unknown granularity %d", level))
+	}
+}
+
+// Clamp returns min if v is less than min, max if v is greater than max, and v
+// otherwise. Clamp panics if min is greater than max.
+func (v Version) Clamp(min, max Version) Version {
+	if min.Compare(max) > 0 {
+		panic(fmt.Sprintf("invalid clamp bounds: min %s is greater than max %s", min.String(), max.String()))
+	}
+	if v.Compare(min) < 0 {
+		return min
+	}
+	if v.Compare(max) > 0 {
+		return max
+	}
+	return v
+}
+
 // IncPatch returns a new version with the patch number incremented by 1.
-// This method returns an error if the version is not a stable version.
+// This method returns an error if the version is not a stable version. As with
+// [Version.AddPatch], the -fips and -incompat markers and build metadata are
+// kept.
 func (v Version) IncPatch() (Version, error) {
-	if v.phase != stable {
+	return v.AddPatch(1)
+}
+
+// AddPatch returns a new version with n added to the patch number, eg
+// "v24.1.3" plus 2 is "v24.1.5". The -fips and -incompat markers and build
+// metadata are kept, so "v24.1.3-fips+enterprise" plus 1 is
+// "v24.1.4-fips+enterprise"; the commit count and SHA of a `git describe`
+// build are dropped. This method returns an error if the version is not a
+// stable version, or if n is negative.
+func (v Version) AddPatch(n int) (Version, error) {
+	if v.phase != Stable {
 		return Version{}, fmt.Errorf("version %s is not a stable version", v.String())
 	}
+	if n < 0 {
+		return Version{}, errors.Newf("cannot add a negative number of patches (%d) to version %s", n, v.String())
+	}
+	if v.patch > math.MaxInt-n {
+		return Version{}, errors.Newf("version %s has the largest possible patch number", v.String())
+	}
 	nextVersion := Version{
-		phase:   v.phase,
-		year:    v.year,
-		ordinal: v.ordinal,
-		patch:   v.patch + 1,
+		phase:         v.phase,
+		year:          v.year,
+		ordinal:       v.ordinal,
+		patch:         v.patch + n,
+		fips:          v.fips,
+		incompat:      v.incompat,
+		buildMetadata: v.buildMetadata,
 	}
-	nextVersion.raw = nextVersion.Format("v%X.%Y.%Z")
+	nextVersion.raw = nextVersion.appendSuffixes(nextVersion.Format("v%X.%Y.%Z"))
 	return nextVersion, nil
 }
 
 // IncPreRelease returns a new version with the pre-release part incremented by 1.
-// This method returns an error if the version is not a pre-release.
+// The -fips and -incompat markers and build metadata are kept. This method
+// returns an error if the version is not a pre-release.
 func (v Version) IncPreRelease() (Version, error) {
 	if !(v.IsPrerelease()) {
 		return Version{}, errors.New("version is not a prerelease")
@@ -390,14 +1626,340 @@ func (v Version) IncPreRelease() (Version, error) {
 	if v.phaseSubOrdinal > 0 || v.customOrdinal > 0 {
 		return Version{}, errors.New("only unmodified CRDB versions are supported")
 	}
+	if v.phaseOrdinal == math.MaxInt {
+		return Version{}, errors.Newf("version %s has the largest possible phase ordinal", v.String())
+	}
 	nextVersion := Version{
-		raw:          v.raw,
-		phase:        v.phase,
+		raw:           v.raw,
+		phase:         v.phase,
+		year:          v.year,
+		ordinal:       v.ordinal,
+		patch:         v.patch,
+		phaseOrdinal:  v.phaseOrdinal + 1,
+		fips:          v.fips,
+		incompat:      v.incompat,
+		buildMetadata: v.buildMetadata,
+	}
+	nextVersion.raw = nextVersion.appendSuffixes(nextVersion.Format("v%X.%Y.%Z-%P.%o"))
+	return nextVersion, nil
+}
+
+// Next returns the next expected version in v's release train: the next patch
+// release of a stable release (see [Version.IncPatch]), eg "v24.1.4" after
+// "v24.1.3", or the next pre-release in the same phase (see
+// [Version.IncPreRelease]), eg "v24.1.0-rc.3" after "v24.1.0-rc.2". Since the
+// package can't know which pre-release is the last of its phase, Next never
+// moves on to a later phase; use [Version.AdvanceToPhase] for that, eg to go
+// from the final "v24.1.0-rc.N" to "v24.1.0".
+//
+// This method returns an error for versions with no well-defined next
+// version: cloudonly, adhoc, and `git describe` builds, and the symbolic
+// [Latest] version.
+func (v Version) Next() (Version, error) {
+	if v.IsCustomBuild() || v.IsAdhocBuild() {
+		return Version{}, errors.Newf("version %s is an adhoc build, which has no next version", v.String())
+	}
+	switch v.phase {
+	case Alpha, Beta, RC:
+		return v.IncPreRelease()
+	case Stable:
+		if v.Empty() {
+			return Version{}, errors.New("the empty version has no next version")
+		}
+		return v.IncPatch()
+	default:
+		return Version{}, errors.Newf("version %s has no next version", v.String())
+	}
+}
+
+// IncCloudOnly returns a new version with the cloudonly sub-ordinal of a
+// cloudonly pre-release incremented by 1, eg "v24.3.0-alpha.1-cloudonly.2"
+// becomes "v24.3.0-alpha.1-cloudonly.3". The result always uses the
+// "-cloudonly.M" form, even if v used the older "-cloudonly-rcM" form, and
+// keeps the -incompat marker and build metadata. This method returns an error if v is not a pre-release with a cloudonly
+// sub-ordinal. A sub-ordinal of 0 (eg "v24.3.0-alpha.1-cloudonly.0") is the
+// same as none: such a version is equal to, and normalizes to, the plain
+// pre-release, so it's an error, too.
+func (v Version) IncCloudOnly() (Version, error) {
+	if !v.IsPrerelease() || v.phaseSubOrdinal == 0 {
+		return Version{}, errors.Newf("version %s is not a cloudonly prerelease", v.String())
+	}
+	if v.phaseSubOrdinal == math.MaxInt {
+		return Version{}, errors.Newf("version %s has the largest possible cloudonly sub-ordinal", v.String())
+	}
+	nextVersion := Version{
+		phase:           v.phase,
+		year:            v.year,
+		ordinal:         v.ordinal,
+		patch:           v.patch,
+		phaseOrdinal:    v.phaseOrdinal,
+		phaseSubOrdinal: v.phaseSubOrdinal + 1,
+		incompat:        v.incompat,
+		buildMetadata:   v.buildMetadata,
+	}
+	nextVersion.raw = nextVersion.appendSuffixes(nextVersion.Format("v%X.%Y.%Z-%P.%o-cloudonly.%s"))
+	return nextVersion, nil
+}
+
+// AdvanceToPhase returns the first version of phase p for the same release as
+// the pre-release v. Advancing to [Beta] or [RC] gives ordinal 1 of that phase
+// (eg, "v24.1.0-alpha.3" advanced to Beta is "v24.1.0-beta.1"), and advancing
+// to [Stable] gives the release itself (eg, "v24.1.0-rc.2" advanced to Stable
+// is "v24.1.0"). Since the number of pre-releases in each phase isn't known in
+// advance, callers must decide when to move on to the next phase; use
+// [Version.IncPreRelease] to stay in the current one.
+//
+// This method returns an error if v is not a pre-release, is a modified
+// version (see [Version.IncPreRelease]), or if p is not a later phase than
+// v's among Alpha, Beta, RC, and Stable.
+func (v Version) AdvanceToPhase(p Phase) (Version, error) {
+	if !v.IsPrerelease() {
+		return Version{}, errors.Newf("version %s is not a prerelease", v.String())
+	}
+	if v.phaseSubOrdinal > 0 || v.customOrdinal > 0 {
+		return Version{}, errors.New("only unmodified CRDB versions are supported")
+	}
+	switch {
+	case p == Stable:
+		return v.WithoutPrerelease(), nil
+	case p != Beta && p != RC:
+		return Version{}, errors.Newf("cannot advance to phase %s", p)
+	case p <= v.phase:
+		return Version{}, errors.Newf("cannot advance %s to an earlier or equal phase %s", v.String(), phaseNames[p])
+	}
+	next := Version{
 		year:         v.year,
 		ordinal:      v.ordinal,
 		patch:        v.patch,
-		phaseOrdinal: v.phaseOrdinal + 1,
+		phase:        p,
+		phaseOrdinal: 1,
 	}
-	nextVersion.raw = nextVersion.Format("v%X.%Y.%Z-%P.%o")
-	return nextVersion, nil
+	next.raw = next.Format("v%X.%Y.%Z-%P.%o")
+	return next, nil
+}
+
+// RebasePrerelease returns a copy of the pre-release version v moved onto the
+// target release series, preserving its patch, phase, and phase ordinals (eg,
+// "v24.1.0-rc.1" rebased onto v24.2 is "v24.2.0-rc.1"). This method returns an
+// error if the version is not a pre-release, or is an adhoc build whose
+// version string can't be rebuilt (see [Version.Normalize]).
+func (v Version) RebasePrerelease(target MajorVersion) (Version, error) {
+	if !v.IsPrerelease() {
+		return Version{}, errors.Newf("version %s is not a prerelease", v.String())
+	}
+	if target.Empty() {
+		return Version{}, errors.New("cannot rebase onto an empty release series")
+	}
+	rebased := v
+	rebased.year, rebased.ordinal = target.Year, target.Ordinal
+	str, ok := rebased.canonical()
+	if !ok {
+		return Version{}, errors.Newf("cannot rebase version %s", v.String())
+	}
+	return Parse(str)
+}
+
+// A PhaseSegment is a range of pre-release versions within a single phase,
+// eg "v24.1.0-beta.1" through "v24.1.0-beta.3".
+type PhaseSegment struct {
+	Phase Phase
+	// FirstOrdinal and LastOrdinal are the (inclusive) range of phase ordinals
+	// in the segment.
+	FirstOrdinal, LastOrdinal int
+}
+
+// PrereleaseSegments returns the phases spanned by the pre-releases from and
+// to (inclusive), in order, along with the range of phase ordinals each phase
+// covers. For example, the span from "v24.1.0-alpha.2" to "v24.1.0-rc.3" might
+// be alpha 2-4, beta 1-3, and rc 1-3.
+//
+// Since the number of pre-releases in each phase isn't known in advance,
+// maxOrdinals must supply the last ordinal of every phase the span passes
+// through (ie, every phase except the one to is in); later phases start at
+// ordinal 1. An error is returned if from and to aren't pre-releases of the
+// same vX.Y.Z, if from is after to, or if maxOrdinals is missing a phase.
+// Sub-ordinals and adhoc build suffixes are ignored.
+func PrereleaseSegments(from, to Version, maxOrdinals map[Phase]int) ([]PhaseSegment, error) {
+	if !from.IsPrerelease() || !to.IsPrerelease() {
+		return nil, errors.Newf("versions %s and %s must both be prereleases", from.String(), to.String())
+	}
+	if !from.WithoutPrerelease().Equals(to.WithoutPrerelease()) {
+		return nil, errors.Newf("versions %s and %s are not prereleases of the same version", from.String(), to.String())
+	}
+	if cmp.Compare(from.phase, to.phase) > 0 ||
+		(from.phase == to.phase && from.phaseOrdinal > to.phaseOrdinal) {
+		return nil, errors.Newf("version %s is after %s", from.String(), to.String())
+	}
+
+	var segments []PhaseSegment
+	for phase := from.phase; phase <= to.phase; phase++ {
+		segment := PhaseSegment{Phase: phase, FirstOrdinal: 1, LastOrdinal: to.phaseOrdinal}
+		if phase == from.phase {
+			segment.FirstOrdinal = from.phaseOrdinal
+		}
+		if phase != to.phase {
+			last, ok := maxOrdinals[phase]
+			if !ok {
+				return nil, errors.Newf("missing the maximum ordinal of the %s phase", phaseNames[phase])
+			}
+			if last < segment.FirstOrdinal {
+				return nil, errors.Newf("maximum ordinal %d of the %s phase is before %d", last, phaseNames[phase], segment.FirstOrdinal)
+			}
+			segment.LastOrdinal = last
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// PatchRange returns the stable releases from from's patch version through
+// to's, in order, eg "v24.1.2" through "v24.1.5" gives v24.1.2, v24.1.3,
+// v24.1.4, and v24.1.5. Only the patch numbers of from and to are used; any
+// pre-release or build suffix is ignored. An error is returned if from and to
+// are in different release series, or if from's patch is greater than to's.
+func PatchRange(from, to Version) ([]Version, error) {
+	if from.Empty() || to.Empty() {
+		return nil, errors.New("cannot compute the patch range of an empty version")
+	}
+	if from.latest || to.latest {
+		return nil, errors.New("cannot compute the patch range of the symbolic version latest")
+	}
+	if !from.Major().Equals(to.Major()) {
+		return nil, errors.Newf("versions %s and %s are in different release series", from.String(), to.String())
+	}
+	if from.patch > to.patch {
+		return nil, errors.Newf("version %s is after %s", from.String(), to.String())
+	}
+	var patches []Version
+	for patch := from.patch; patch <= to.patch; patch++ {
+		patches = append(patches, from.Major().patch(patch))
+	}
+	return patches, nil
+}
+
+// SortKey returns a printable string such that comparing the sort keys of two
+// versions byte-wise (eg, with strings.Compare, or in a store that orders keys
+// lexically) gives the same result as [Version.Compare]. In particular,
+// versions which are [Version.Equals] have the same sort key, even if they
+// were parsed from differently-spelled strings. Sort keys can't be parsed back
+// into versions.
+//
+// Each integer field is written in decimal, preceded by its number of digits
+// as two digits (eg, 24 is "0224", and 1 is "011"), so integers of up to 99 digits are
+// supported; since parsed fields fit in an int, this covers every version. The
+// adhoc label is written identifier by identifier (see [Version.Compare]):
+// numeric identifiers, with leading zeros removed, are preceded by their
+// number of digits as four digits, so numeric identifiers of up to 9999
+// significant digits are ordered correctly. The sort key of [Latest] is "9",
+// which sorts after every other key, since the number of digits in the year is
+// at most "19".
+func (v Version) SortKey() string {
+	if v.latest {
+		return "9"
+	}
+	var sb strings.Builder
+	writeInt := func(n int) {
+		digits := strconv.Itoa(n)
+		fmt.Fprintf(&sb, "%02d%s", len(digits), digits)
+	}
+	writeInt(v.year)
+	writeInt(v.ordinal)
+	writeInt(v.patch)
+	sb.WriteString(strconv.Itoa(int(v.phase)))
+	writeInt(v.phaseOrdinal)
+	writeInt(v.phaseSubOrdinal)
+	writeInt(v.customOrdinal)
+
+	// Each identifier is written as a type ("1" for numeric, "2" otherwise),
+	// its contents, and a "!" terminator, which sorts before every character
+	// that may appear in a label. The label ends with a "0", so that it sorts
+	// before any longer label that it's a prefix of, followed by the label
+	// itself to break ties (eg, between "build.01" and "build.1").
+	if v.adhocLabel != "" {
+		for _, ident := range strings.Split(v.adhocLabel, ".") {
+			if isNumeric(ident) {
+				digits := strings.TrimLeft(ident, "0")
+				fmt.Fprintf(&sb, "1%04d%s!", len(digits), digits)
+			} else {
+				fmt.Fprintf(&sb, "2%s!", ident)
+			}
+		}
+	}
+	sb.WriteString("0")
+	sb.WriteString(v.adhocLabel)
+	sb.WriteString("!")
+
+	if v.incompat {
+		sb.WriteString("1")
+	} else {
+		sb.WriteString("0")
+	}
+	// a FIPS build's key extends the otherwise-equal non-FIPS build's key, so
+	// that it sorts just after it, and keys of non-FIPS builds are unchanged
+	if v.fips {
+		sb.WriteString("1")
+	}
+	return sb.String()
+}
+
+// stableBytesLayout identifies the layout produced by [Version.StableBytes].
+// It must be incremented whenever the layout changes.
+const stableBytesLayout = 2
+
+// StableBytes returns a canonical encoding of the version's semantic fields,
+// suitable for use as (or as input to) a content-addressed key. The raw string
+// is not included, so versions which are [Version.Equals] have equal encodings.
+//
+// The encoding is stable across machines and releases of this package. Its
+// layout (version 2) is, in order:
+//
+//   - 1 byte: the layout version, currently 2
+//   - 8 bytes each, big-endian: year, ordinal, and patch
+//   - 1 byte: the phase (see [Phase])
+//   - 8 bytes each, big-endian: phase ordinal, phase sub-ordinal, and custom
+//     ordinal
+//   - 4 bytes, big-endian: the length of the adhoc label, followed by the label
+//   - 1 byte: flags; bit 0 is set for -incompat builds, bit 1 is set for the
+//     symbolic [Latest] version (whose other fields are zero), bit 2 is set
+//     for -fips builds, and the other bits are reserved and zero
+//
+// Any change to the layout will be accompanied by a new layout version.
+// Layout 1 was the same, except that only bit 0 of the flags was used; the
+// versions it could encode are encoded the same way by layout 2, apart from
+// the layout version itself.
+func (v Version) StableBytes() []byte {
+	b := make([]byte, 0, 1+8*6+1+4+len(v.adhocLabel)+1)
+	b = append(b, stableBytesLayout)
+	b = binary.BigEndian.AppendUint64(b, uint64(v.year))
+	b = binary.BigEndian.AppendUint64(b, uint64(v.ordinal))
+	b = binary.BigEndian.AppendUint64(b, uint64(v.patch))
+	b = append(b, byte(v.phase))
+	b = binary.BigEndian.AppendUint64(b, uint64(v.phaseOrdinal))
+	b = binary.BigEndian.AppendUint64(b, uint64(v.phaseSubOrdinal))
+	b = binary.BigEndian.AppendUint64(b, uint64(v.customOrdinal))
+	b = binary.BigEndian.AppendUint32(b, uint32(len(v.adhocLabel)))
+	b = append(b, v.adhocLabel...)
+	var flags byte
+	if v.incompat {
+		flags |= 1 << 0
+	}
+	if v.latest {
+		flags |= 1 << 1
+	}
+	if v.fips {
+		flags |= 1 << 2
+	}
+	return append(b, flags)
+}
+
+// Hash returns a fingerprint of the version derived from its comparison fields
+// (see [Version.StableBytes]), so that versions which are [Version.Equals] but
+// were parsed from differently-spelled strings have the same hash. Unlike the
+// Version itself, the hash can be used as a map key to dedupe versions by
+// semantic equality. Hashes are stable across process runs and machines, but
+// change when the StableBytes layout version does.
+func (v Version) Hash() uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(v.StableBytes())
+	return h.Sum64()
 }
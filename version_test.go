@@ -15,8 +15,13 @@
 package version
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
+	"regexp"
 	"slices"
 	"strings"
 	"testing"
@@ -47,12 +52,87 @@ func TestVersion_Getters(t *testing.T) {
 
 	require.Equal(t, MajorVersion{1, 2}, v.Major())
 	require.Equal(t, 3, v.Patch())
+	require.Equal(t, 1, v.PhaseOrdinal())
+	require.Equal(t, 0, v.PhaseSubOrdinal())
+	require.Equal(t, 0, v.CustomOrdinal())
 
 	require.True(t, v.IsPrerelease())
 	require.False(t, v.IsCloudOnlyBuild())
 	require.False(t, v.IsCustomOrAdhocBuild())
 }
 
+func TestVersion_OrdinalGetters(t *testing.T) {
+	v := MustParse("v24.1.0-rc.3-cloudonly.2")
+	require.Equal(t, 3, v.PhaseOrdinal())
+	require.Equal(t, 2, v.PhaseSubOrdinal())
+	require.Equal(t, 0, v.CustomOrdinal())
+
+	v = MustParse("v24.1.0-beta.1-12-gabcdef1")
+	require.Equal(t, 1, v.PhaseOrdinal())
+	require.Equal(t, 0, v.PhaseSubOrdinal())
+	require.Equal(t, 12, v.CustomOrdinal())
+
+	v = MustParse("v24.1.3")
+	require.Equal(t, 0, v.PhaseOrdinal())
+	require.Equal(t, 0, v.PhaseSubOrdinal())
+	require.Equal(t, 0, v.CustomOrdinal())
+}
+
+func TestVersion_FinalizationSeries(t *testing.T) {
+	testCases := []struct {
+		version string
+		want    MajorVersion
+	}{
+		// GA versions
+		{"v24.1.0", MajorVersion{24, 1}},
+		{"v24.1.7", MajorVersion{24, 1}},
+		{"v23.2.0-cloudonly.1", MajorVersion{23, 2}},
+		{"v24.1.3-12-gabcdef1", MajorVersion{24, 1}},
+
+		// prereleases target the series they precede
+		{"v24.2.0-alpha.1", MajorVersion{24, 2}},
+		{"v24.2.0-beta.3", MajorVersion{24, 2}},
+		{"v24.2.0-rc.2", MajorVersion{24, 2}},
+		{"v24.3.0-alpha.1-cloudonly.1", MajorVersion{24, 3}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			require.Equal(t, tc.want, MustParse(tc.version).FinalizationSeries())
+		})
+	}
+}
+
+func TestVersion_FormatTemplate(t *testing.T) {
+	testCases := []struct {
+		version string
+		tmpl    string
+		want    string
+	}{
+		{"v24.1.3", "{{.Year}}.{{.Ordinal}}-custom", "24.1-custom"},
+		{"v24.1.0-rc.2", "v{{.Year}}.{{.Ordinal}}.{{.Patch}}-{{.Phase}}.{{.PhaseOrdinal}}", "v24.1.0-rc.2"},
+		{"v24.1.0-beta.1-cloudonly.3", "{{.Phase}}/{{.PhaseSubOrdinal}}", "beta/3"},
+		{"v24.1.0-12-gabcdef1", "{{if .CustomOrdinal}}{{.CustomOrdinal}} commits past {{end}}{{.Raw}}", "12 commits past v24.1.0-12-gabcdef1"},
+		{"v23.1.0-swenson-mr-4", "{{.AdhocLabel}}", "swenson-mr-4"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			got, err := MustParse(tc.version).FormatTemplate(tc.tmpl)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		v := MustParse("v24.1.0")
+
+		_, err := v.FormatTemplate("{{.Year")
+		require.ErrorContains(t, err, "parsing version template")
+
+		_, err = v.FormatTemplate("{{.Nope}}")
+		require.ErrorContains(t, err, "executing version template")
+	})
+}
+
 func TestVersion_IsPrerelease(t *testing.T) {
 	// Valid pre-release versions
 	require.True(t, MustParse("v20.2.0-beta.3").IsPrerelease())
@@ -79,6 +159,38 @@ func TestVersion_IsPrerelease(t *testing.T) {
 	require.False(t, MustParse("v23.2.0-cloudonly2").IsPrerelease())
 }
 
+func TestVersion_PhasePredicates(t *testing.T) {
+	testCases := []struct {
+		version          string
+		isAlpha, isBeta  bool
+		isRC, prerelease bool
+	}{
+		{version: "v24.1.0-alpha.1", isAlpha: true, prerelease: true},
+		{version: "v24.1.0-alpha.1-cloudonly.2", isAlpha: true, prerelease: true},
+		{version: "v21.1.0-alpha.3-2846-g7ae3ac92f7", isAlpha: true, prerelease: true},
+		{version: "v24.1.0-beta.2", isBeta: true, prerelease: true},
+		{version: "v23.2.0-beta.1-cloudonly-rc1", isBeta: true, prerelease: true},
+		{version: "v24.1.0-rc.2", isRC: true, prerelease: true},
+		{version: "v24.1.0-rc.2-14-gabcdef", isRC: true, prerelease: true},
+		{version: "v23.2.0-rc.2-cloudonly-rc2", isRC: true, prerelease: true},
+		{version: "v24.1.0-cloudonly.1"},
+		{version: "v24.1.0"},
+		{version: "v24.1.0-14-gabcdef"},
+		{version: "v24.1.0-rc1-with-hyphen"},
+		{version: "v24.1.0-customLabel"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			v := MustParse(tc.version)
+			require.Equal(t, tc.isAlpha, v.IsAlpha())
+			require.Equal(t, tc.isBeta, v.IsBeta())
+			require.Equal(t, tc.isRC, v.IsRC())
+			require.Equal(t, tc.isRC, v.IsReleaseCandidate())
+			require.Equal(t, tc.prerelease, v.IsPrerelease())
+		})
+	}
+}
+
 func TestVersion_CustomAndAdhocBuilds(t *testing.T) {
 	builds := []struct {
 		version string
@@ -148,6 +260,42 @@ func TestVersion_CustomAndAdhocBuilds(t *testing.T) {
 	})
 }
 
+func TestVersion_CommitsSinceTag(t *testing.T) {
+	testCases := []struct {
+		version string
+		commits int
+		ok      bool
+	}{
+		// git describe builds
+		{"v24.1.0-12-gabcdef1", 12, true},
+		{"v21.1.0-rc.2-163-g122c66f436", 163, true},
+		{"v22.2.10-1-g7b8322d67c-fips", 1, true},
+		{"v24.1.0-37-gabcdef", 37, true},
+
+		// arbitrary labels
+		{"v23.2.0-arbitrary-adhoc-label", 0, false},
+		{"v23.1.0-swenson-mr-4", 0, false},
+
+		// image digests
+		{"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build", 0, false},
+
+		// not adhoc at all
+		{"v24.1.0", 0, false},
+		{"v24.1.0-rc.1", 0, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			commits, ok := MustParse(tc.version).CommitsSinceTag()
+			require.Equal(t, tc.commits, commits)
+			require.Equal(t, tc.ok, ok)
+
+			commits, ok = MustParse(tc.version).CommitsSinceRelease()
+			require.Equal(t, tc.commits, commits)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
 func TestVersion_IsCloudOnlyBuild(t *testing.T) {
 	// Valid pre-release versions
 	require.False(t, MustParse("v20.2.0-beta.3").IsCloudOnlyBuild())
@@ -175,6 +323,58 @@ func TestVersion_IsCloudOnlyBuild(t *testing.T) {
 	require.True(t, MustParse("v23.2.0-cloudonly2").IsCloudOnlyBuild())
 }
 
+func TestVersion_BreaksWireCompat(t *testing.T) {
+	testCases := []struct {
+		marked, unmarked string
+	}{
+		{"v24.1.0-incompat", "v24.1.0"},
+		{"v24.1.0-rc.1-incompat", "v24.1.0-rc.1"},
+		{"v24.1.3-fips-incompat", "v24.1.3-fips"},
+		{"v24.1.0-rc.1-12-gabcdef1-incompat", "v24.1.0-rc.1-12-gabcdef1"},
+		{"v24.1.0-foo-incompat", "v24.1.0-foo"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.marked, func(t *testing.T) {
+			marked := MustParse(tc.marked)
+			unmarked := MustParse(tc.unmarked)
+
+			require.True(t, marked.BreaksWireCompat())
+			require.False(t, unmarked.BreaksWireCompat())
+			require.Equal(t, tc.marked, marked.String())
+			require.Equal(t, unmarked.Major(), marked.Major())
+			require.Equal(t, unmarked.IsPrerelease(), marked.IsPrerelease())
+			require.Equal(t, 1, marked.Compare(unmarked))
+			require.True(t, marked.Normalize().BreaksWireCompat())
+		})
+	}
+}
+
+func TestVersion_Redacted(t *testing.T) {
+	testCases := []struct {
+		version string
+		want    string
+	}{
+		{"v24.1.0", "v24.1.0"},
+		{"v24.1.0-rc.1", "v24.1.0-rc.1"},
+		{"v24.1.0-cloudonly.2", "v24.1.0-cloudonly.2"},
+		{"v21.1.0-1-g9cbe7c5281", "v21.1.0"},
+		{"v22.2.10-1-g7b8322d67c-fips", "v22.2.10-fips"},
+		{"v23.1.0-alpha.1-1643-gdf8e73734e-fips", "v23.1.0-alpha.1-fips"},
+		{"v24.2.1-rc.3-cloudonly.1-12-gabcd124", "v24.2.1-rc.3-cloudonly.1"},
+		{"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build", "latest-v22.2-build"},
+		{"v23.1.0-swenson-mr-4", "v23.1.0-swenson-mr-4"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			got := MustParse(tc.version).Redacted()
+			require.Equal(t, tc.want, got)
+			for _, secret := range []string{"9cbe7c5281", "7b8322d67c", "df8e73734e", "abcd124", "6bbf8437"} {
+				require.NotContains(t, got, secret)
+			}
+		})
+	}
+}
+
 func TestParse(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		testData := []string{
@@ -203,6 +403,12 @@ func TestParse(t *testing.T) {
 			// these may not actually exist, but are parseable
 			"v1.1.2-beta.20190101+metadata",
 			"v1.2.3-rc1-with-hyphen+metadata-with-hyphen",
+			"v1.2.3+metadata",
+			"v1.2.3+metadata-with-hyphen",
+			"v1.2.3+metadata.with.dots",
+			"v24.1.0+enterprise",
+			"v24.1.0-rc.1+gpu",
+			"v24.1.0-rc.1-incompat+gpu",
 		}
 		for _, str := range testData {
 			v, err := Parse(str)
@@ -244,14 +450,13 @@ func TestParse(t *testing.T) {
 			"v1.2.3-beta$",
 			"v1.2.3-bet;a",
 			"v1.2.3+metadata%",
+			"v1.2.3+",
+			"v1.2.3+meta+data",
 			"v01.2.3",
 			"v1.02.3",
 			"v1.2.03",
 			"v1.0.0-rc1-with-hyphen",
 			"v1.0.0-rc2.dot.dot",
-			"v1.2.3+metadata",
-			"v1.2.3+metadata-with-hyphen",
-			"v1.2.3+metadata.with.dots",
 		}
 		for _, str := range testData {
 			_, err := Parse(str)
@@ -271,7 +476,7 @@ func TestParse(t *testing.T) {
 					year:         24,
 					ordinal:      2,
 					patch:        0,
-					phase:        alpha,
+					phase:        Alpha,
 					phaseOrdinal: 1,
 				},
 			},
@@ -281,7 +486,7 @@ func TestParse(t *testing.T) {
 					year:            24,
 					ordinal:         2,
 					patch:           0,
-					phase:           alpha,
+					phase:           Alpha,
 					phaseOrdinal:    1,
 					phaseSubOrdinal: 2,
 				},
@@ -292,7 +497,7 @@ func TestParse(t *testing.T) {
 					year:         24,
 					ordinal:      2,
 					patch:        0,
-					phase:        beta,
+					phase:        Beta,
 					phaseOrdinal: 2,
 				},
 			},
@@ -302,7 +507,7 @@ func TestParse(t *testing.T) {
 					year:            24,
 					ordinal:         2,
 					patch:           0,
-					phase:           beta,
+					phase:           Beta,
 					phaseOrdinal:    2,
 					phaseSubOrdinal: 3,
 				},
@@ -314,7 +519,7 @@ func TestParse(t *testing.T) {
 					year:         24,
 					ordinal:      2,
 					patch:        0,
-					phase:        cloudonly,
+					phase:        CloudOnly,
 					phaseOrdinal: 4,
 				},
 			},
@@ -325,7 +530,7 @@ func TestParse(t *testing.T) {
 					year:    24,
 					ordinal: 2,
 					patch:   0,
-					phase:   stable,
+					phase:   Stable,
 				},
 			},
 			{
@@ -334,7 +539,7 @@ func TestParse(t *testing.T) {
 					year:    24,
 					ordinal: 2,
 					patch:   4,
-					phase:   stable,
+					phase:   Stable,
 				},
 			},
 			{
@@ -343,7 +548,7 @@ func TestParse(t *testing.T) {
 					year:         24,
 					ordinal:      2,
 					patch:        4,
-					phase:        cloudonly,
+					phase:        CloudOnly,
 					phaseOrdinal: 2,
 				},
 			},
@@ -354,7 +559,7 @@ func TestParse(t *testing.T) {
 					year:          24,
 					ordinal:       2,
 					patch:         3,
-					phase:         stable,
+					phase:         Stable,
 					customOrdinal: 12,
 				},
 			},
@@ -368,6 +573,270 @@ func TestParse(t *testing.T) {
 	})
 }
 
+func TestParseBounded(t *testing.T) {
+	for _, str := range []string{"v19.1.0", "v24.1.3", "v30.2.0-rc.1", "v24.1.0-12-gabcdef1"} {
+		v, err := ParseBounded(str, 19, 30)
+		require.NoError(t, err)
+		require.Equal(t, MustParse(str), v)
+	}
+
+	for _, str := range []string{"v240.1.0", "v2.1.0", "v18.2.9", "v31.1.0"} {
+		_, err := ParseBounded(str, 19, 30)
+		require.ErrorIs(t, err, ErrYearOutOfRange, str)
+		require.ErrorContains(t, err, "is not between 19 and 30")
+	}
+
+	_, err := ParseBounded("24.1.0", 19, 30)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrYearOutOfRange)
+}
+
+func TestParseImageTag(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, tc := range []struct {
+			image   string
+			version string
+		}{
+			{"cockroachdb/cockroach:v24.1.3", "v24.1.3"},
+			{"docker.io/cockroachdb/cockroach:v24.1.0-rc.1", "v24.1.0-rc.1"},
+			{"localhost:5000/cockroachdb/cockroach:v23.2.0-12-gabcdef1", "v23.2.0-12-gabcdef1"},
+			{":v24.1.3", "v24.1.3"},
+			{"localhost:5000/cockroach:v24.1.3", "v24.1.3"},
+			{
+				"cockroachdb/cockroach:v24.1.3@sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11",
+				"v24.1.3",
+			},
+			{
+				"localhost:5000/cockroachdb/cockroach:v24.1.0-rc.1@sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11",
+				"v24.1.0-rc.1",
+			},
+			{
+				"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+				"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+			},
+		} {
+			v, err := ParseImageTag(tc.image)
+			require.NoError(t, err)
+			require.Equal(t, MustParse(tc.version), v)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, image := range []string{
+			"",
+			"cockroachdb/cockroach",
+			"cockroachdb/cockroach:latest",
+			"localhost:5000/cockroachdb/cockroach",
+			"host:5000/repo",
+			"host:5000/repo@sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11",
+			"cockroachdb/cockroach:v24.1.3@",
+			"cockroachdb/cockroach@sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11",
+		} {
+			_, err := ParseImageTag(image)
+			require.Errorf(t, err, "expected error parsing '%s'", image)
+		}
+	})
+}
+
+func TestParseBuildOutput(t *testing.T) {
+	output := "Build Tag:        v24.1.3\r\n" +
+		"Build Time:       2024/07/22 17:51:21\r\n" +
+		"Distribution:     CCL\r\n" +
+		"Platform:         linux amd64 (x86_64-pc-linux-gnu)\r\n" +
+		"Go Version:       go1.22.5 X:nocoverageredesign\r\n" +
+		"C Compiler:       gcc 6.5.0\r\n" +
+		"Build Commit ID:  8bbd2ce7a2e5a0c7d4b0b1b2a1cbd6b8e5a3c4d2\r\n" +
+		"Build Type:       release\r\n"
+	v, err := ParseBuildOutput(output)
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.3"), v)
+
+	v, err = ParseBuildOutput("some preamble\n  Build Tag: v24.1.0-rc.1\n")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.0-rc.1"), v)
+
+	_, err = ParseBuildOutput("Build Time: 2024/07/22 17:51:21\n")
+	require.ErrorContains(t, err, "no Build Tag line")
+	_, err = ParseBuildOutput("")
+	require.ErrorContains(t, err, "no Build Tag line")
+	_, err = ParseBuildOutput("Build Tag: 24.1.3\n")
+	require.ErrorContains(t, err, "invalid Build Tag")
+}
+
+func TestParsePrefix(t *testing.T) {
+	const (
+		invalid    = "invalid"
+		incomplete = "incomplete"
+		complete   = "complete"
+	)
+	for _, tc := range []struct {
+		input string
+		want  string
+	}{
+		// typing "v24.1.0-rc.1" one character at a time
+		{"", incomplete},
+		{"v", incomplete},
+		{"v2", incomplete},
+		{"v24", incomplete},
+		{"v24.", incomplete},
+		{"v24.1", incomplete},
+		{"v24.1.", incomplete},
+		{"v24.1.0", complete},
+		{"v24.1.0-", incomplete},
+		{"v24.1.0-r", complete}, // a valid adhoc label
+		{"v24.1.0-rc", complete},
+		{"v24.1.0-rc.", complete},
+		{"v24.1.0-rc.1", complete},
+
+		// build metadata
+		{"v24.1.0+", incomplete},
+		{"v24.1.0+e", complete},
+		{"v24.1.0-rc.1+gpu.2", complete},
+		{"v24.1.0+a+", invalid},
+		{"v24.1.0+a%", invalid},
+
+		// mistakes that can't be fixed by typing more
+		{"24", invalid},
+		{"V24", invalid},
+		{"v0", invalid},
+		{"v24.0", invalid},
+		{"v24..", invalid},
+		{"v24.1.01", invalid},
+		{"v24.1.0.", invalid},
+		{"v24.1.0-rc;", invalid},
+		{" v24.1.0", invalid},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			isComplete, isValid := ParsePrefix(tc.input)
+			require.Equal(t, tc.want == complete, isComplete)
+			require.Equal(t, tc.want != invalid, isValid)
+		})
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, str := range []string{
+			"v24.1.0",
+			"v24.1.0-rc.1",
+			"v21.1.0-1-g9cbe7c5",
+			"v21.1.0-1-g9cbe7c5281",
+			"v23.1.0-alpha.1-1643-gdf8e73734e-fips",
+			"v24.1.0-1-g0123456789abcdef0123456789abcdef01234567",
+			"v24.1.0-cloudonly.1",
+			"v23.1.12-cloudonly-rc1",
+			"v24.3.0-alpha.1-cloudonly.1",
+			"v24.1.0-rc.1-incompat",
+			"v24.1.0-rc.1+gpu",
+		} {
+			v, err := ParseStrict(str)
+			require.NoError(t, err)
+			require.Equal(t, MustParse(str), v)
+		}
+	})
+
+	t.Run("sha length", func(t *testing.T) {
+		for _, str := range []string{
+			"v24.1.0-1-g0",
+			"v24.1.0-1-gabcdef",
+			"v24.1.0-rc.1-3-gabc",
+			"v24.1.0-1-g0123456789abcdef0123456789abcdef012345678",
+		} {
+			// lenient parsing accepts any length of SHA...
+			_, err := Parse(str)
+			require.NoError(t, err)
+
+			// ...but strict parsing does not
+			_, err = ParseStrict(str)
+			require.ErrorContains(t, err, "must be 7-40 hex characters")
+
+			// errors report the original string
+			_, err = ParseStrict(str + "+meta")
+			require.ErrorContains(t, err, "invalid version string '"+str+"+meta'")
+		}
+	})
+
+	t.Run("adhoc labels", func(t *testing.T) {
+		for _, str := range []string{
+			"v24.1.0-rcx",
+			"v24.1.0-RC.1",
+			"v23.1.0-swenson-mr-4",
+			"v24.1.0-rc.1-incompat-foo",
+			"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+		} {
+			_, err := Parse(str)
+			require.NoError(t, err)
+
+			_, err = ParseStrict(str)
+			require.ErrorContains(t, err, "is not allowed")
+		}
+	})
+}
+
+func TestVersionNormalize(t *testing.T) {
+	testCases := []struct {
+		raw  string
+		want string
+	}{
+		{"v24.1.0", "v24.1.0"},
+		{"v24.1.0-fips", "v24.1.0-fips"},
+		{"v24.1.0-rc.1", "v24.1.0-rc.1"},
+		{"v23.1.0-alpha.4-fips", "v23.1.0-alpha.4-fips"},
+		{"v24.1.3-fips-incompat+gpu", "v24.1.3-fips-incompat+gpu"},
+		{"v23.2.0-alpha.00000000", "v23.2.0-alpha.0"},
+
+		// cloudonly sub-ordinals
+		{"v24.3.0-alpha.1-cloudonly.1", "v24.3.0-alpha.1-cloudonly.1"},
+		{"v23.2.0-beta.1-cloudonly-rc1", "v23.2.0-beta.1-cloudonly.1"},
+
+		// the many shapes of cloudonly
+		{"v23.2.0-cloudonly", "v23.2.0-cloudonly.0"},
+		{"v23.2.0-cloudonly.1", "v23.2.0-cloudonly.1"},
+		{"v23.2.0-cloudonly2", "v23.2.0-cloudonly.2"},
+		{"v23.1.12-cloudonly-rc2", "v23.1.12-cloudonly.2"},
+
+		// adhoc labels
+		{"v23.1.0-swenson-mr-4", "v23.1.0-swenson-mr-4"},
+
+		// can't be rebuilt, so they're unchanged
+		{"v24.1.0-12-gabcdef1", "v24.1.0-12-gabcdef1"},
+		{"v23.1.0-alpha.1-1643-gdf8e73734e-fips", "v23.1.0-alpha.1-1643-gdf8e73734e-fips"},
+		{"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build", "sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			v := MustParse(tc.raw)
+			normalized := v.Normalize()
+			require.Equal(t, tc.want, normalized.String())
+			require.True(t, v.Equals(normalized))
+			require.Equal(t, v.IsFIPS(), normalized.IsFIPS())
+			require.Equal(t, normalized, normalized.Normalize())
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		require.Equal(t, Version{}, Version{}.Normalize())
+	})
+
+	t.Run("cloudonly separators", func(t *testing.T) {
+		dashed := MustParse("v24.1.0-beta.1-cloudonly-rc2")
+		dotted := MustParse("v24.1.0-beta.1-cloudonly.2")
+
+		// String keeps the original spelling
+		require.Equal(t, "v24.1.0-beta.1-cloudonly-rc2", dashed.String())
+		require.Equal(t, "v24.1.0-beta.1-cloudonly.2", dotted.String())
+		require.False(t, dashed.EqualsRaw(dotted))
+
+		// both normalize to the same, parseable, dotted form
+		for _, v := range []Version{dashed, dotted} {
+			normalized := v.Normalize()
+			require.Equal(t, "v24.1.0-beta.1-cloudonly.2", normalized.String())
+			require.Equal(t, normalized, MustParse(normalized.String()))
+		}
+		require.True(t, dashed.Normalize().EqualsRaw(dotted.Normalize()))
+	})
+}
+
 func TestVersionCompare(t *testing.T) {
 	const aEqualsB = "equal"
 	const aLessThanB = "less than"
@@ -558,6 +1027,96 @@ func TestVersionCompare(t *testing.T) {
 	}
 }
 
+func TestVersionCompareCore(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"v24.1.0-rc.1-12-gabcdef1", "v24.1.0-rc.1", 0},
+		{"v24.1.0-12-gabcdef1", "v24.1.0-3-g1234567", 0},
+		{"v24.1.0-12-gabcdef1", "v24.1.0", 0},
+		{"v24.1.0-incompat", "v24.1.0", 0},
+		{"v24.1.0-foo", "v24.1.0-bar", 0},
+		{"v24.1.0-cloudonly-rc1", "v24.1.0-cloudonly.1", 0},
+		{"v24.1.0-foo", "v24.1.0", 1},
+		{"v24.1.0-rc.1-12-gabcdef1", "v24.1.0-rc.2", -1},
+		{"v24.1.0-beta.1-cloudonly.2", "v24.1.0-beta.1-cloudonly.1", 1},
+		{"v24.1.1", "v24.1.0-99-gabcdef1", 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" vs "+tc.b, func(t *testing.T) {
+			a, b := MustParse(tc.a), MustParse(tc.b)
+			require.Equal(t, tc.want, a.CompareCore(b))
+			require.Equal(t, -tc.want, b.CompareCore(a))
+		})
+	}
+	require.Equal(t, 1, Latest.CompareCore(MustParse("v24.1.0")))
+}
+
+func TestVersionCompareSafe(t *testing.T) {
+	a := MustParse("v24.1.0")
+	b := MustParse("v24.1.0-rc.1")
+
+	rslt, err := a.CompareSafe(b)
+	require.NoError(t, err)
+	require.Equal(t, 1, rslt)
+
+	rslt, err = b.CompareSafe(a)
+	require.NoError(t, err)
+	require.Equal(t, -1, rslt)
+
+	_, err = a.CompareSafe(Version{})
+	require.Error(t, err)
+	_, err = Version{}.CompareSafe(a)
+	require.Error(t, err)
+}
+
+func TestVersionDiff(t *testing.T) {
+	testCases := []struct {
+		a, b  string
+		field string
+		cmp   int
+	}{
+		{"v24.1.0", "v24.1.0", "", 0},
+		{"v24.1.0-cloudonly.1", "v24.1.0-cloudonly-rc1", "", 0},
+		{"v23.2.0", "v24.1.0", "year", -1},
+		{"v24.2.0", "v24.1.0", "ordinal", 1},
+		{"v24.1.3", "v24.1.10", "patch", -1},
+		{"v24.1.0-rc.1", "v24.1.0", "phase", -1},
+		{"v24.1.0-rc.2", "v24.1.0-rc.1", "phaseOrdinal", 1},
+		{"v24.1.0-rc.1-cloudonly.1", "v24.1.0-rc.1-cloudonly.2", "phaseSubOrdinal", -1},
+		{"v24.1.0-2-gabcdef1", "v24.1.0-1-gabcdef1", "customOrdinal", 1},
+		{"v24.1.0-bar", "v24.1.0-foo", "adhocLabel", -1},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s vs %s", tc.a, tc.b), func(t *testing.T) {
+			a, b := MustParse(tc.a), MustParse(tc.b)
+			field, cmp := a.Diff(b)
+			require.Equal(t, tc.field, field)
+			require.Equal(t, tc.cmp, cmp)
+			require.Equal(t, a.Compare(b), cmp)
+		})
+	}
+}
+
+func TestVersionSemanticallyEqual(t *testing.T) {
+	a, b := MustParse("v24.1.0-cloudonly.1"), MustParse("v24.1.0-cloudonly-rc1")
+	require.True(t, a.SemanticallyEqual(b))
+	require.NotEqual(t, a, b)
+	require.False(t, MustParse("v24.1.3-fips").SemanticallyEqual(MustParse("v24.1.3")))
+	require.False(t, MustParse("v24.1.3").SemanticallyEqual(MustParse("v24.1.4")))
+}
+
+func TestVersionEqualsRaw(t *testing.T) {
+	a := MustParse("v24.1.0-cloudonly.1")
+	b := MustParse("v24.1.0-cloudonly-rc1")
+	require.True(t, a.Equals(b))
+	require.False(t, a.EqualsRaw(b))
+
+	require.True(t, a.EqualsRaw(MustParse("v24.1.0-cloudonly.1")))
+	require.True(t, Version{}.EqualsRaw(Version{}))
+}
+
 func TestVersionOrdering(t *testing.T) {
 	testCases := []struct {
 		name  string
@@ -579,6 +1138,32 @@ func TestVersionOrdering(t *testing.T) {
 			input: []string{"v21.1.0-1-g9cbe7c5281", "v21.1.0", "v21.1.0-rc.1"},
 			want:  []string{"v21.1.0-rc.1", "v21.1.0", "v21.1.0-1-g9cbe7c5281"},
 		},
+		{
+			name: "sorts sha256 latest builds after vX.Y.0 and before vX.Y.1",
+			input: []string{
+				"v22.2.1",
+				"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+				"v22.2.0-rc.1",
+				"v22.2.1-alpha.0",
+				"v22.2.0",
+				"v22.2.0-12-gabcdef1",
+				"v22.2.0-cloudonly.1",
+			},
+			want: []string{
+				"v22.2.0-rc.1",
+				"v22.2.0-cloudonly.1",
+				"v22.2.0",
+				"v22.2.0-12-gabcdef1",
+				"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+				"v22.2.1-alpha.0",
+				"v22.2.1",
+			},
+		},
+		{
+			name:  "sorts numeric parts of adhoc labels numerically",
+			input: []string{"v21.1.0-build.10", "v21.1.0-build.2", "v21.1.0-build", "v21.1.0-build.a", "v21.1.0-build.2.1", "v21.1.0-build.9"},
+			want:  []string{"v21.1.0-build", "v21.1.0-build.2", "v21.1.0-build.2.1", "v21.1.0-build.9", "v21.1.0-build.10", "v21.1.0-build.a"},
+		},
 		{
 			name:  "sorts nonstandard adhoc builds after corresponding normal version",
 			input: []string{"v21.1.0-customLabel", "v21.1.0", "v21.1.0-rc.1"},
@@ -603,6 +1188,55 @@ func TestVersionOrdering(t *testing.T) {
 	}
 }
 
+func TestVersionCompareCloudOnlyShapes(t *testing.T) {
+	// Each group holds the different spellings of one cloudonly version, in
+	// ascending order; versions in the same group compare equal.
+	groups := [][]string{
+		{"v24.1.0-rc.1"},
+		{"v24.1.0-rc.1-cloudonly.1", "v24.1.0-rc.1-cloudonly-rc1"},
+		{"v24.1.0-rc.1-cloudonly.2", "v24.1.0-rc.1-cloudonly-rc2"},
+		{"v24.1.0-rc.2"},
+		{"v24.1.0-cloudonly"},
+		{"v24.1.0-cloudonly.1", "v24.1.0-cloudonly-rc1", "v24.1.0-cloudonly1"},
+		{"v24.1.0-cloudonly.2", "v24.1.0-cloudonly-rc2", "v24.1.0-cloudonly2"},
+		{"v24.1.0-cloudonly.10", "v24.1.0-cloudonly-rc10", "v24.1.0-cloudonly10"},
+		{"v24.1.0"},
+	}
+	for i, group := range groups {
+		for _, a := range group {
+			for j, other := range groups {
+				for _, b := range other {
+					want := cmp.Compare(i, j)
+					require.Equal(t, want, MustParse(a).Compare(MustParse(b)), "%s vs %s", a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestCompareAdhocLabels(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"build.2", "build.10", -1},
+		{"build.10", "build.10", 0},
+		{"build.01", "build.1", -1},
+		{"build.1", "build.01", 1},
+		{"1", "a", -1},
+		{"a.1", "a", 1},
+		{"rc1-with-hyphen", "rc2", -1},
+		{"build.99999999999999999999", "build.100000000000000000000", -1},
+		{"build.00000000000000000002", "build.10", -1},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s vs %s", tc.a, tc.b), func(t *testing.T) {
+			require.Equal(t, tc.want, compareAdhocLabels(tc.a, tc.b))
+			require.Equal(t, -tc.want, compareAdhocLabels(tc.b, tc.a))
+		})
+	}
+}
+
 func TestAtLeast(t *testing.T) {
 	testCases := []struct {
 		cockroachVersion string
@@ -629,6 +1263,94 @@ func TestAtLeast(t *testing.T) {
 	}
 }
 
+func TestVersionBetween(t *testing.T) {
+	testCases := []struct {
+		v, lo, hi                string
+		loInclusive, hiInclusive bool
+		want                     bool
+	}{
+		{"v24.1.0-rc.1", "v24.1.0-rc.1", "v24.1.0", true, false, true},
+		{"v24.1.0-rc.1", "v24.1.0-rc.1", "v24.1.0", false, false, false},
+		{"v24.1.0", "v24.1.0-rc.1", "v24.1.0", true, false, false},
+		{"v24.1.0", "v24.1.0-rc.1", "v24.1.0", true, true, true},
+		{"v24.1.0-cloudonly.1", "v24.1.0-rc.1", "v24.1.0", false, false, true},
+		{"v23.2.9", "v24.1.0", "v24.2.0", true, true, false},
+		{"v24.2.1", "v24.1.0", "v24.2.0", true, true, false},
+
+		// lo == hi
+		{"v24.1.0", "v24.1.0", "v24.1.0", true, true, true},
+		{"v24.1.0", "v24.1.0", "v24.1.0", true, false, false},
+		{"v24.1.0", "v24.1.0", "v24.1.0", false, true, false},
+		{"v24.1.1", "v24.1.0", "v24.1.0", true, true, false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s in %s..%s (%t, %t)", tc.v, tc.lo, tc.hi, tc.loInclusive, tc.hiInclusive), func(t *testing.T) {
+			v := MustParse(tc.v)
+			require.Equal(t, tc.want, v.Between(MustParse(tc.lo), MustParse(tc.hi), tc.loInclusive, tc.hiInclusive))
+		})
+	}
+}
+
+func TestVersionMinMax(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		min, max string
+	}{
+		{"v24.1.0", "v24.1.3", "v24.1.0", "v24.1.3"},
+		{"v24.2.0", "v24.1.3", "v24.1.3", "v24.2.0"},
+		{"v24.1.0-rc.1", "v24.1.0", "v24.1.0-rc.1", "v24.1.0"},
+		{"v24.1.0", "v24.1.0-1-gabcdef1", "v24.1.0", "v24.1.0-1-gabcdef1"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s, %s", tc.a, tc.b), func(t *testing.T) {
+			a, b := MustParse(tc.a), MustParse(tc.b)
+			require.Equal(t, MustParse(tc.min), a.Min(b))
+			require.Equal(t, MustParse(tc.min), b.Min(a))
+			require.Equal(t, MustParse(tc.max), a.Max(b))
+			require.Equal(t, MustParse(tc.max), b.Max(a))
+		})
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		a := MustParse("v24.1.0-cloudonly.1")
+		b := MustParse("v24.1.0-cloudonly-rc1")
+		require.True(t, a.Min(b).Equals(a))
+		require.True(t, a.Max(b).Equals(b))
+	})
+}
+
+func TestVersionClamp(t *testing.T) {
+	min := MustParse("v24.1.0")
+	max := MustParse("v24.2.3")
+
+	testCases := []struct {
+		v, want string
+	}{
+		{"v23.2.9", "v24.1.0"},
+		{"v24.1.0-rc.1", "v24.1.0"},
+		{"v24.1.0", "v24.1.0"},
+		{"v24.1.5", "v24.1.5"},
+		{"v24.2.3", "v24.2.3"},
+		{"v24.2.3-1-gabcdef1", "v24.2.3"},
+		{"v25.1.0", "v24.2.3"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v, func(t *testing.T) {
+			require.Equal(t, MustParse(tc.want), MustParse(tc.v).Clamp(min, max))
+		})
+	}
+
+	t.Run("min equals max", func(t *testing.T) {
+		require.Equal(t, min, MustParse("v25.1.0").Clamp(min, min))
+	})
+
+	t.Run("min greater than max", func(t *testing.T) {
+		require.PanicsWithValue(t, "invalid clamp bounds: min v24.2.3 is greater than max v24.1.0", func() {
+			MustParse("v24.1.5").Clamp(max, min)
+		})
+	})
+}
+
 func TestVersionCanBeAMapKey(t *testing.T) {
 	// not a real test, but a reminder that Version needs to be hashable
 	_ = make(map[Version]bool)
@@ -663,6 +1385,34 @@ func TestScan(t *testing.T) {
 	require.ErrorContains(t, err, "cannot convert int to Version")
 }
 
+func TestWithoutPrerelease(t *testing.T) {
+	testCases := []struct {
+		version string
+		want    string
+	}{
+		{"v24.1.0", "v24.1.0"},
+		{"v24.1.3", "v24.1.3"},
+		{"v24.1.0-alpha.1", "v24.1.0"},
+		{"v24.1.0-rc.2", "v24.1.0"},
+		{"v24.1.0-beta.1-cloudonly.2", "v24.1.0"},
+		{"v24.1.2-cloudonly.1", "v24.1.2"},
+		{"v24.1.3-alpha.1", "v24.1.3"},
+		{"v24.1.3-fips", "v24.1.3"},
+		{"v24.1.0-rc.1-12-gabcdef1", "v24.1.0"},
+		{"v24.1.3-12-gabcdef1", "v24.1.3"},
+		{"v24.1.3-customLabel", "v24.1.3"},
+		{"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build", "v22.2.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			got := MustParse(tc.version).WithoutPrerelease()
+			require.Equal(t, MustParse(tc.want), got)
+			require.Equal(t, tc.want, got.String())
+			require.Equal(t, got, MustParse(tc.version).TargetRelease())
+		})
+	}
+}
+
 func TestIncPatch(t *testing.T) {
 	testCases := []struct {
 		currentVersion string
@@ -680,6 +1430,11 @@ func TestIncPatch(t *testing.T) {
 		{"v21.1.3", "v21.1.4", false},
 		{"v20.2.11", "v20.2.12", false},
 		{"v21.1.0", "v21.1.1", false},
+		{"v24.1.3-fips", "v24.1.4-fips", false},
+		{"v24.1.3-incompat", "v24.1.4-incompat", false},
+		{"v24.1.3+enterprise", "v24.1.4+enterprise", false},
+		{"v24.1.3-fips-incompat+enterprise", "v24.1.4-fips-incompat+enterprise", false},
+		{"v24.1.3-12-gabcdef1-incompat", "v24.1.4-incompat", false},
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Version.IncPatch #%d: %s -> %s", i, tc.currentVersion, tc.nextVersion), func(t *testing.T) {
@@ -695,15 +1450,52 @@ func TestIncPatch(t *testing.T) {
 	}
 }
 
-func TestIncPreRelease(t *testing.T) {
+func TestAddPatch(t *testing.T) {
 	testCases := []struct {
 		currentVersion string
+		n              int
 		nextVersion    string
 		expectError    bool
 	}{
-		{"v21.2.0-alpha.1", "v21.2.0-alpha.2", false},
-		{"v21.1.0-beta.3", "v21.1.0-beta.4", false},
-		{"v21.1.0-rc.3", "v21.1.0-rc.4", false},
+		{"v24.1.3", 0, "v24.1.3", false},
+		{"v24.1.3", 1, "v24.1.4", false},
+		{"v24.1.3", 10, "v24.1.13", false},
+		{"v24.1.3-fips", 2, "v24.1.5-fips", false},
+		{"v24.1.3-incompat+gpu", 2, "v24.1.5-incompat+gpu", false},
+		{"v24.1.3", -1, "", true},
+		{"v24.1.0-rc.1", 1, "", true},
+		{"v24.1.0-cloudonly.1", 1, "", true},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Version.AddPatch #%d: %s + %d", i, tc.currentVersion, tc.n), func(t *testing.T) {
+			a := MustParse(tc.currentVersion)
+			b, err := a.AddPatch(tc.n)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, MustParse(tc.nextVersion), b)
+		})
+	}
+
+	v := MustParse("v24.1.3")
+	_, err := v.AddPatch(math.MaxInt)
+	require.ErrorContains(t, err, "largest possible patch number")
+}
+
+func TestIncPreRelease(t *testing.T) {
+	testCases := []struct {
+		currentVersion string
+		nextVersion    string
+		expectError    bool
+	}{
+		{"v21.2.0-alpha.1", "v21.2.0-alpha.2", false},
+		{"v21.1.0-beta.3", "v21.1.0-beta.4", false},
+		{"v21.1.0-rc.3", "v21.1.0-rc.4", false},
+		{"v23.1.0-alpha.4-fips", "v23.1.0-alpha.5-fips", false},
+		{"v24.1.0-rc.1-incompat", "v24.1.0-rc.2-incompat", false},
+		{"v24.1.0-beta.2-fips-incompat+gpu", "v24.1.0-beta.3-fips-incompat+gpu", false},
 		{"v21.1.0-rc.3-cloudonly.1", "", true},
 		{"v21.1.0-cloudonly.1", "", true},
 		{"v21.1.0", "", true},
@@ -727,3 +1519,1218 @@ func TestIncPreRelease(t *testing.T) {
 		})
 	}
 }
+
+func TestRebasePrerelease(t *testing.T) {
+	testCases := []struct {
+		currentVersion string
+		target         string
+		nextVersion    string
+		expectError    bool
+	}{
+		{"v24.1.0-rc.1", "v24.2", "v24.2.0-rc.1", false},
+		{"v24.1.0-alpha.3", "v25.1", "v25.1.0-alpha.3", false},
+		{"v24.1.0-beta.2-cloudonly.1", "v24.3", "v24.3.0-beta.2-cloudonly.1", false},
+		{"v24.1.2-rc.1", "v23.2", "v23.2.2-rc.1", false},
+		{"v24.1.0-rc.1-fips", "v24.2", "v24.2.0-rc.1-fips", false},
+		{"v24.1.0-beta.2-fips-incompat", "v24.2", "v24.2.0-beta.2-fips-incompat", false},
+		{"v24.1.0", "v24.2", "", true},
+		{"v24.1.0-cloudonly.1", "v24.2", "", true},
+		{"v24.1.0-customLabel", "v24.2", "", true},
+		{"v24.1.0-rc.1-12-gabcdef1", "v24.2", "", true},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Version.RebasePrerelease #%d: %s -> %s", i, tc.currentVersion, tc.target), func(t *testing.T) {
+			a := MustParse(tc.currentVersion)
+			b, err := a.RebasePrerelease(MustParseMajorVersion(tc.target))
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, MustParse(tc.nextVersion), b)
+			require.Equal(t, tc.nextVersion, b.String())
+
+			// and back again
+			c, err := b.RebasePrerelease(a.Major())
+			require.NoError(t, err)
+			require.True(t, a.Equals(c))
+			require.Equal(t, a.IsFIPS(), c.IsFIPS())
+		})
+	}
+}
+
+func TestPrereleaseSegments(t *testing.T) {
+	maxOrdinals := map[Phase]int{Alpha: 4, Beta: 3}
+
+	t.Run("alpha to rc", func(t *testing.T) {
+		segments, err := PrereleaseSegments(MustParse("v24.1.0-alpha.2"), MustParse("v24.1.0-rc.3"), maxOrdinals)
+		require.NoError(t, err)
+		require.Equal(t, []PhaseSegment{
+			{Phase: Alpha, FirstOrdinal: 2, LastOrdinal: 4},
+			{Phase: Beta, FirstOrdinal: 1, LastOrdinal: 3},
+			{Phase: RC, FirstOrdinal: 1, LastOrdinal: 3},
+		}, segments)
+	})
+
+	t.Run("beta to rc", func(t *testing.T) {
+		segments, err := PrereleaseSegments(MustParse("v24.1.0-beta.3"), MustParse("v24.1.0-rc.1"), maxOrdinals)
+		require.NoError(t, err)
+		require.Equal(t, []PhaseSegment{
+			{Phase: Beta, FirstOrdinal: 3, LastOrdinal: 3},
+			{Phase: RC, FirstOrdinal: 1, LastOrdinal: 1},
+		}, segments)
+	})
+
+	t.Run("single phase", func(t *testing.T) {
+		segments, err := PrereleaseSegments(MustParse("v24.1.0-rc.1"), MustParse("v24.1.0-rc.2"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []PhaseSegment{{Phase: RC, FirstOrdinal: 1, LastOrdinal: 2}}, segments)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			from, to string
+		}{
+			{"v24.1.0-rc.1", "v24.1.0"},
+			{"v24.1.0-cloudonly.1", "v24.1.0-rc.1"},
+			{"v24.1.0-alpha.1", "v24.2.0-rc.1"},
+			{"v24.1.0-alpha.1", "v24.1.1-rc.1"},
+			{"v24.1.0-rc.1", "v24.1.0-beta.1"},
+			{"v24.1.0-rc.2", "v24.1.0-rc.1"},
+			{"v24.1.0-alpha.5", "v24.1.0-beta.1"}, // past the max alpha
+		} {
+			_, err := PrereleaseSegments(MustParse(tc.from), MustParse(tc.to), maxOrdinals)
+			require.Errorf(t, err, "expected error for %s to %s", tc.from, tc.to)
+		}
+
+		_, err := PrereleaseSegments(MustParse("v24.1.0-alpha.1"), MustParse("v24.1.0-rc.1"), map[Phase]int{Alpha: 4})
+		require.ErrorContains(t, err, "missing the maximum ordinal of the beta phase")
+	})
+}
+
+func TestPatchRange(t *testing.T) {
+	patches, err := PatchRange(MustParse("v24.1.2"), MustParse("v24.1.4"))
+	require.NoError(t, err)
+	require.Equal(t, []Version{MustParse("v24.1.2"), MustParse("v24.1.3"), MustParse("v24.1.4")}, patches)
+	for _, patch := range patches {
+		require.Equal(t, patch.String(), patch.Format("v%X.%Y.%Z"))
+	}
+
+	// Pre-release suffixes are dropped.
+	patches, err = PatchRange(MustParse("v24.1.0-rc.1"), MustParse("v24.1.1"))
+	require.NoError(t, err)
+	require.Equal(t, []Version{MustParse("v24.1.0"), MustParse("v24.1.1")}, patches)
+
+	// Only patch numbers are compared, so a release and a pre-release of the
+	// same patch give just that patch.
+	patches, err = PatchRange(MustParse("v24.1.3"), MustParse("v24.1.3-rc.1"))
+	require.NoError(t, err)
+	require.Equal(t, []Version{MustParse("v24.1.3")}, patches)
+	patches, err = PatchRange(MustParse("v24.1.3-12-gabcdef1"), MustParse("v24.1.4-alpha.1"))
+	require.NoError(t, err)
+	require.Equal(t, []Version{MustParse("v24.1.3"), MustParse("v24.1.4")}, patches)
+
+	_, err = PatchRange(MustParse("v24.1.2"), MustParse("v24.2.4"))
+	require.ErrorContains(t, err, "different release series")
+	_, err = PatchRange(MustParse("v24.1.4"), MustParse("v24.1.2"))
+	require.ErrorContains(t, err, "is after")
+	_, err = PatchRange(Version{}, MustParse("v24.1.2"))
+	require.Error(t, err)
+}
+
+func TestStableBytes(t *testing.T) {
+	require.Equal(t, []byte{
+		2,                       // layout
+		0, 0, 0, 0, 0, 0, 0, 24, // year
+		0, 0, 0, 0, 0, 0, 0, 1, // ordinal
+		0, 0, 0, 0, 0, 0, 0, 2, // patch
+		3,                      // phase (rc)
+		0, 0, 0, 0, 0, 0, 0, 1, // phase ordinal
+		0, 0, 0, 0, 0, 0, 0, 0, // phase sub-ordinal
+		0, 0, 0, 0, 0, 0, 0, 0, // custom ordinal
+		0, 0, 0, 0, // adhoc label length
+		0, // flags
+	}, MustParse("v24.1.2-rc.1").StableBytes())
+
+	require.Equal(t, []byte{
+		2,
+		0, 0, 0, 0, 0, 0, 0, 23,
+		0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		6, // phase (adhoc)
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 4, 'f', 'o', 'o', '1',
+		1, // flags (incompat)
+	}, MustParse("v23.1.0-foo1-incompat").StableBytes())
+
+	require.Equal(t, []byte{
+		2,
+		0, 0, 0, 0, 0, 0, 0, 24,
+		0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 3,
+		5, // phase (stable)
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0,
+		5, // flags (incompat, fips)
+	}, MustParse("v24.1.3-fips-incompat").StableBytes())
+
+	require.Equal(t, []byte{
+		2,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, // no phase
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0,
+		2, // flags (latest)
+	}, Latest.StableBytes())
+
+	// Layout 1 had only the incompat flag. Layout 2 encodes the versions that
+	// layout 1 could encode in the same way, apart from the layout byte, so
+	// layout 1 encodings keep their meaning.
+	for str, layout1 := range map[string][]byte{
+		"v24.1.2-rc.1": {
+			1,
+			0, 0, 0, 0, 0, 0, 0, 24,
+			0, 0, 0, 0, 0, 0, 0, 1,
+			0, 0, 0, 0, 0, 0, 0, 2,
+			3,
+			0, 0, 0, 0, 0, 0, 0, 1,
+			0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 0,
+			0,
+		},
+		"v23.1.0-foo1-incompat": {
+			1,
+			0, 0, 0, 0, 0, 0, 0, 23,
+			0, 0, 0, 0, 0, 0, 0, 1,
+			0, 0, 0, 0, 0, 0, 0, 0,
+			6,
+			0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 4, 'f', 'o', 'o', '1',
+			1,
+		},
+	} {
+		b := MustParse(str).StableBytes()
+		require.Equal(t, layout1[1:], b[1:], str)
+	}
+
+	// the raw string isn't part of the encoding
+	require.Equal(t, MustParse("v23.1.11-cloudonly2").StableBytes(), MustParse("v23.1.11-cloudonly.2").StableBytes())
+	require.NotEqual(t, MustParse("v24.1.0").StableBytes(), MustParse("v24.1.1").StableBytes())
+}
+
+func TestBuildMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		raw      string
+		metadata string
+		base     string
+	}{
+		{"v24.1.0+enterprise", "enterprise", "v24.1.0"},
+		{"v24.1.0-rc.1+gpu", "gpu", "v24.1.0-rc.1"},
+		{"v24.1.0-rc.1-12-gabcdef1+build.5-x", "build.5-x", "v24.1.0-rc.1-12-gabcdef1"},
+		{"v24.1.0-incompat+gpu", "gpu", "v24.1.0-incompat"},
+		{"v24.1.0", "", "v24.1.0"},
+	} {
+		t.Run(tc.raw, func(t *testing.T) {
+			v := MustParse(tc.raw)
+			require.Equal(t, tc.metadata, v.BuildMetadata())
+			require.Equal(t, tc.raw, v.String())
+
+			// metadata is ignored for ordering
+			base := MustParse(tc.base)
+			require.Equal(t, 0, v.Compare(base))
+			require.True(t, v.Equals(base))
+			require.Empty(t, base.BuildMetadata())
+		})
+	}
+
+	// historical adhoc labels may contain "+", which is part of the label
+	for _, tc := range []struct {
+		raw, label string
+	}{
+		{"v24.1.0-foo+a", "foo+a"},
+		{"v24.1.0-foo+a+b", "foo+a+b"},
+		{"v24.1.0-foo+a+b-incompat", "foo+a+b"},
+		{"v24.1.0-foo+", "foo+"},
+		{"v24.1.0-foo.1-incompat+gpu", "foo.1-incompat+gpu"},
+	} {
+		v := MustParse(tc.raw)
+		require.Equal(t, Adhoc, v.Phase(), tc.raw)
+		require.Equal(t, tc.label, v.adhocLabel, tc.raw)
+		require.Empty(t, v.BuildMetadata(), tc.raw)
+		require.Equal(t, tc.raw, v.String())
+		require.Equal(t, v, v.Normalize(), tc.raw)
+	}
+	require.False(t, MustParse("v24.1.0-foo+a").Equals(MustParse("v24.1.0-foo+b")))
+	require.NotEqual(t, MustParse("v24.1.0-foo+a").Hash(), MustParse("v24.1.0-foo+b").Hash())
+
+	for _, str := range []string{"v24.1.0+a+b", "v24.1.0+a$", "v24.1.0+", "v24.1.0-rc.1+", "v24.1.0-12-gabcdef1+"} {
+		_, err := Parse(str)
+		require.ErrorContains(t, err, "invalid version string '"+str+"': build metadata", str)
+	}
+
+	require.Equal(t, "v24.1.0-cloudonly.2+gpu", MustParse("v24.1.0-cloudonly2+gpu").Normalize().String())
+	require.True(t, MustParse("v24.1.0-incompat+gpu").BreaksWireCompat())
+	require.True(t, MustParse("v24.1.0-rc.1+gpu").IsPrerelease())
+}
+
+func TestHash(t *testing.T) {
+	a := MustParse("v24.1.0-cloudonly.1")
+	b := MustParse("v24.1.0-cloudonly-rc1")
+	c := MustParse("v24.1.0-cloudonly1")
+	require.NotEqual(t, a, b)
+	require.Equal(t, a.Hash(), b.Hash())
+	require.Equal(t, a.Hash(), c.Hash())
+
+	seen := map[uint64]Version{}
+	for _, v := range []Version{a, b, c} {
+		seen[v.Hash()] = v
+	}
+	require.Len(t, seen, 1)
+
+	require.NotEqual(t, a.Hash(), MustParse("v24.1.0-cloudonly.2").Hash())
+	require.NotEqual(t, a.Hash(), MustParse("v24.1.0").Hash())
+	require.NotEqual(t, MustParse("v24.1.0").Hash(), MustParse("v24.1.0-incompat").Hash())
+
+	// the hash is stable across runs, for a given StableBytes layout
+	require.Equal(t, uint64(0x18d368da6e4e6347), MustParse("v24.1.0").Hash())
+}
+
+func TestAdvanceToPhase(t *testing.T) {
+	for _, tc := range []struct {
+		v    string
+		p    Phase
+		want string
+	}{
+		{"v24.1.0-alpha.3", Beta, "v24.1.0-beta.1"},
+		{"v24.1.0-alpha.3", RC, "v24.1.0-rc.1"},
+		{"v24.1.0-alpha.3", Stable, "v24.1.0"},
+		{"v24.1.0-beta.2", RC, "v24.1.0-rc.1"},
+		{"v24.1.2-rc.4", Stable, "v24.1.2"},
+	} {
+		next, err := MustParse(tc.v).AdvanceToPhase(tc.p)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, next.String())
+		require.Equal(t, MustParse(tc.want), next)
+	}
+
+	for _, tc := range []struct {
+		v string
+		p Phase
+	}{
+		// backwards or no-op
+		{"v24.1.0-rc.1", Alpha},
+		{"v24.1.0-rc.1", Beta},
+		{"v24.1.0-beta.1", Beta},
+		// not a pre-release phase
+		{"v24.1.0-alpha.1", CloudOnly},
+		{"v24.1.0-alpha.1", Adhoc},
+		// not a pre-release
+		{"v24.1.0", Stable},
+		{"v24.1.0-cloudonly.1", Stable},
+		// modified versions
+		{"v24.1.0-alpha.1-cloudonly.1", Beta},
+		{"v24.1.0-alpha.1-12-gabcdef1", Beta},
+	} {
+		_, err := MustParse(tc.v).AdvanceToPhase(tc.p)
+		require.Errorf(t, err, "expected error advancing %s to %s", tc.v, tc.p)
+	}
+
+	_, err := MustParse("v24.1.0-alpha.1").AdvanceToPhase(CloudOnly)
+	require.ErrorContains(t, err, "cannot advance to phase cloudonly")
+}
+
+func TestParseBestEffort(t *testing.T) {
+	v, ok := ParseBestEffort("v24.1.3-rc.1")
+	require.True(t, ok)
+	require.Equal(t, MustParse("v24.1.3-rc.1"), v)
+
+	for _, tc := range []struct {
+		input string
+		want  string
+	}{
+		{"v24.1", "v24.1.0"},
+		{"v24.1.x", "v24.1.0"},
+		{"v24.1.03", "v24.1.0"},
+		{"v24.2-beta", "v24.2.0"},
+		{"v24.1.3-bad$label", "v24.1.0"},
+	} {
+		v, ok := ParseBestEffort(tc.input)
+		require.False(t, ok, tc.input)
+		require.Equal(t, tc.want, v.String())
+		require.Equal(t, MustParse(tc.want), v)
+
+		// the salvaged version round-trips
+		blob, err := json.Marshal(v)
+		require.NoError(t, err)
+		var parsed Version
+		require.NoError(t, json.Unmarshal(blob, &parsed))
+		require.Equal(t, v, parsed)
+		require.Equal(t, fmt.Sprintf("version.MustParse(%q)", tc.want), fmt.Sprintf("%#v", v))
+	}
+
+	for _, input := range []string{"", "garbage", "24.1.3", "v24", "v24.01"} {
+		v, ok := ParseBestEffort(input)
+		require.False(t, ok, input)
+		require.Equal(t, Version{}, v, input)
+	}
+}
+
+func TestIsSelfHostedAvailable(t *testing.T) {
+	for _, str := range []string{
+		"v24.1.0",
+		"v24.1.3-12-gabcdef1",
+		"v23.1.0-swenson-mr-4",
+		"v24.1.0-fips",
+		"v24.1.0+enterprise",
+	} {
+		require.True(t, MustParse(str).IsSelfHostedAvailable(), str)
+	}
+	for _, str := range []string{
+		"v24.1.0-alpha.1",
+		"v24.1.0-beta.2",
+		"v24.1.0-rc.1",
+		"v24.1.0-rc.1-12-gabcdef1",
+		"v24.1.0-cloudonly.1",
+		"v23.1.11-cloudonly2",
+		"v24.3.0-alpha.1-cloudonly.1",
+	} {
+		require.False(t, MustParse(str).IsSelfHostedAvailable(), str)
+	}
+	require.False(t, Version{}.IsSelfHostedAvailable())
+}
+
+func TestArtifactStem(t *testing.T) {
+	testCases := []struct {
+		version string
+		want    string
+	}{
+		{"v24.1.3", "v24.1.3"},
+		{"v24.1.3-fips", "v24.1.3-fips"},
+		{"v24.1.0-rc.1", "v24.1.0-rc.1"},
+		{"v24.1.0-beta.1-cloudonly-rc2", "v24.1.0-beta.1-cloudonly-rc2"},
+		{"v23.1.0-alpha.1-1643-gdf8e73734e-fips", "v23.1.0-alpha.1-1643-gdf8e73734e-fips"},
+		{"v23.1.0-swenson-mr-4", "v23.1.0-swenson-mr-4"},
+		{"v24.1.0-rc.1-incompat+gpu", "v24.1.0-rc.1-incompat+gpu"},
+		{
+			"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+			"sha256-6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11-latest-v22.2-build",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			require.Equal(t, tc.want, MustParse(tc.version).ArtifactStem())
+		})
+	}
+	require.Equal(t, "", Version{}.ArtifactStem())
+}
+
+func TestLabelValue(t *testing.T) {
+	testCases := []struct {
+		version string
+		want    string
+	}{
+		{"v24.1.3", "v24.1.3"},
+		{"v24.1.3-fips", "v24.1.3-fips"},
+		{"v24.1.0-beta.1-cloudonly-rc2", "v24.1.0-beta.1-cloudonly-rc2"},
+		{"v24.1.0-rc.1-incompat+gpu", "v24.1.0-rc.1-incompat_gpu"},
+		{"v23.1.0-alpha.1-1643-gdf8e73734e-fips", "v23.1.0-alpha.1-1643-gdf8e73734e-fips"},
+		{
+			"v24.1.0-12-g0123456789abcdef0123456789abcdef01234567",
+			"v24.1.0-12-g0123456789",
+		},
+		{
+			"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+			"latest-v22.2-build_6bbf843734d1",
+		},
+		{
+			"v24.1.0-a.very.long.adhoc.label.that.goes.on.and.on.and.on.and.on.forever",
+			"v24.1.0-a.very.long.adhoc.label.that.goes.on.and.on.and.on.and",
+		},
+	}
+	labelValueRE := regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			label := MustParse(tc.version).LabelValue()
+			require.Equal(t, tc.want, label)
+			require.LessOrEqual(t, len(label), 63)
+			require.Regexp(t, labelValueRE, label)
+		})
+	}
+	require.Equal(t, "", Version{}.LabelValue())
+}
+
+func TestGoString(t *testing.T) {
+	v := MustParse("v24.1.0-rc.1")
+	require.Equal(t, `version.MustParse("v24.1.0-rc.1")`, fmt.Sprintf("%#v", v))
+	require.Equal(t, "v24.1.0-rc.1", fmt.Sprintf("%v", v))
+	require.Equal(t, "v24.1.0-rc.1", fmt.Sprintf("%s", v))
+	require.Equal(t, "version.Version{}", fmt.Sprintf("%#v", Version{}))
+	require.Equal(t, `[]version.Version{version.MustParse("v24.1.0")}`, fmt.Sprintf("%#v", []Version{MustParse("v24.1.0")}))
+}
+
+func TestIncCloudOnly(t *testing.T) {
+	for _, tc := range []struct {
+		v    string
+		want string
+	}{
+		{"v24.3.0-alpha.1-cloudonly.1", "v24.3.0-alpha.1-cloudonly.2"},
+		{"v24.3.0-rc.2-cloudonly.9", "v24.3.0-rc.2-cloudonly.10"},
+		{"v23.2.0-beta.1-cloudonly-rc1", "v23.2.0-beta.1-cloudonly.2"},
+		{"v24.3.0-rc.1-cloudonly.1-incompat+gpu", "v24.3.0-rc.1-cloudonly.2-incompat+gpu"},
+	} {
+		next, err := MustParse(tc.v).IncCloudOnly()
+		require.NoError(t, err)
+		require.Equal(t, tc.want, next.String())
+		require.Equal(t, MustParse(tc.want), next)
+	}
+
+	for _, str := range []string{
+		"v24.3.0",
+		"v24.3.0-alpha.1",
+		"v24.3.0-cloudonly.1",
+		"v23.1.11-cloudonly2",
+		"v24.3.0-alpha.1-12-gabcdef1",
+
+		// a sub-ordinal of 0 is the same as none
+		"v24.3.0-alpha.1-cloudonly.0",
+		"v24.3.0-alpha.1-cloudonly-rc0",
+
+		// build metadata isn't a cloudonly suffix
+		"v24.3.0-alpha.1+cloudonly",
+		"v24.3.0-alpha.1+build-cloudonly.2",
+	} {
+		_, err := MustParse(str).IncCloudOnly()
+		require.Error(t, err, str)
+	}
+
+	// equal versions are treated the same, however they're spelled
+	for _, str := range []string{"v24.3.0-rc.1-cloudonly.0", "v23.2.0-beta.1-cloudonly-rc1"} {
+		v := MustParse(str)
+		next, err := v.IncCloudOnly()
+		nextNormalized, errNormalized := v.Normalize().IncCloudOnly()
+		require.Equal(t, err == nil, errNormalized == nil, str)
+		require.True(t, next.Equals(nextNormalized), str)
+	}
+}
+
+func TestWithBuildMetadata(t *testing.T) {
+	testCases := []struct {
+		v, meta, want string
+	}{
+		{"v24.1.3", "enterprise", "v24.1.3+enterprise"},
+		{"v24.1.3+enterprise", "gpu.2", "v24.1.3+gpu.2"},
+		{"v24.1.3+enterprise", "", "v24.1.3"},
+		{"v24.1.3", "", "v24.1.3"},
+		{"v24.1.0-rc.1-fips", "build-7", "v24.1.0-rc.1-fips+build-7"},
+		{"v24.1.0-beta.1-cloudonly-rc2", "x", "v24.1.0-beta.1-cloudonly-rc2+x"},
+		{"v24.1.0-12-gabcdef1-incompat", "x", "v24.1.0-12-gabcdef1-incompat+x"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v+" with "+tc.meta, func(t *testing.T) {
+			v := MustParse(tc.v)
+			got, err := v.WithBuildMetadata(tc.meta)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.String())
+			require.Equal(t, tc.meta, got.BuildMetadata())
+			require.Equal(t, got, MustParse(got.String()))
+
+			// build metadata doesn't affect comparisons
+			require.Equal(t, 0, got.Compare(v))
+			for _, w := range mustParseAll("v24.1.0-rc.1", "v24.1.0", "v24.1.3", "v24.1.4") {
+				require.Equal(t, v.Compare(w), got.Compare(w), w.String())
+			}
+		})
+	}
+
+	for _, meta := range []string{"a+b", "a_b", "a b", "é"} {
+		_, err := MustParse("v24.1.3").WithBuildMetadata(meta)
+		require.ErrorContains(t, err, "invalid build metadata", meta)
+	}
+	// a "+" after an adhoc label is part of the label
+	_, err := MustParse("v24.1.0-foo").WithBuildMetadata("x")
+	require.Error(t, err)
+	_, err = Version{}.WithBuildMetadata("x")
+	require.Error(t, err)
+	_, err = Latest.WithBuildMetadata("x")
+	require.Error(t, err)
+}
+
+func TestIsFIPS(t *testing.T) {
+	for _, str := range []string{
+		"v24.1.3-fips",
+		"v23.1.0-alpha.4-fips",
+		"v22.2.10-1-g7b8322d67c-fips",
+		"v24.1.3-fips-incompat",
+	} {
+		v := MustParse(str)
+		require.True(t, v.IsFIPS(), str)
+	}
+	for _, str := range []string{"v24.1.3", "v23.1.0-alpha.4", "v24.1.0-cloudonly.1"} {
+		require.False(t, MustParse(str).IsFIPS(), str)
+	}
+
+	// FIPS builds sort just after their non-FIPS counterparts
+	require.Equal(t, 1, MustParse("v24.1.3-fips").Compare(MustParse("v24.1.3")))
+	require.Equal(t, -1, MustParse("v24.1.3-fips").Compare(MustParse("v24.1.4")))
+}
+
+func TestCompareFlags(t *testing.T) {
+	// every combination of flags on the same base version, in order
+	for _, base := range []string{"v24.1.3", "v24.1.0-rc.1", "v24.1.0-12-gabcdef1"} {
+		t.Run(base, func(t *testing.T) {
+			ordered := mustParseAll(base, base+"-fips", base+"-incompat", base+"-fips-incompat")
+			for i := range ordered {
+				for j := range ordered {
+					a, b := ordered[i], ordered[j]
+					want := cmp.Compare(i, j)
+					require.Equalf(t, want, a.Compare(b), "%s vs %s", a, b)
+					require.Equalf(t, want, strings.Compare(a.SortKey(), b.SortKey()), "%s vs %s", a, b)
+					require.Equalf(t, want == 0, bytes.Equal(a.StableBytes(), b.StableBytes()), "%s vs %s", a, b)
+				}
+			}
+		})
+	}
+}
+
+func TestIsSameBuildExceptFIPS(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want bool
+	}{
+		{"v24.1.3-fips", "v24.1.3", true},
+		{"v24.1.3", "v24.1.3-fips", true},
+		{"v24.1.3", "v24.1.3", true},
+		{"v24.1.3-fips", "v24.1.3-fips", true},
+		{"v22.2.10-1-g7b8322d67c-fips", "v22.2.10-1-g7b8322d67c", true},
+		{"v24.1.3-fips-incompat", "v24.1.3-incompat", true},
+		{"v24.1.3-fips", "v24.1.4", false},
+		{"v24.1.3-fips-incompat", "v24.1.3", false},
+		{"v24.1.0-rc.1-fips", "v24.1.0", false},
+		{"v24.1.3+enterprise", "v24.1.3", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" vs "+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.want, MustParse(tc.a).IsSameBuildExceptFIPS(MustParse(tc.b)))
+		})
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	testCases := []struct {
+		str  string
+		want string
+	}{
+		{"v24.1.0-RC.1", "v24.1.0-rc.1"},
+		{"v24.1.0-Beta.2", "v24.1.0-beta.2"},
+		{"v24.1.0-ALPHA.1-fips", "v24.1.0-alpha.1-fips"},
+		{"v24.1.0-Beta.1-CloudOnly-RC2", "v24.1.0-beta.1-cloudonly-rc2"},
+		{"v24.1.0-CLOUDONLY.1+Metadata", "v24.1.0-cloudonly.1+Metadata"},
+		{"v24.1.0-rc.1", "v24.1.0-rc.1"},
+		{"v24.1.0", "v24.1.0"},
+
+		// labels which don't become a phase are kept as-is
+		{"v23.1.0-Swenson-mr-4", "v23.1.0-Swenson-mr-4"},
+		{"v24.1.0-Beta", "v24.1.0-Beta"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.str, func(t *testing.T) {
+			v, err := ParseLenient(tc.str)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, v.String())
+			require.Equal(t, MustParse(tc.want), v)
+		})
+	}
+
+	_, err := ParseLenient("24.1.0-RC.1")
+	require.Error(t, err)
+
+	// Parse remains case-sensitive
+	require.Equal(t, Adhoc, MustParse("v24.1.0-RC.1").Phase())
+}
+
+func TestParseSymbolic(t *testing.T) {
+	v, symbolic, err := ParseSymbolic("latest")
+	require.NoError(t, err)
+	require.True(t, symbolic)
+	require.True(t, v.IsLatest())
+	require.Equal(t, Latest, v)
+	require.Equal(t, "latest", v.String())
+	require.Equal(t, "version.Latest", fmt.Sprintf("%#v", v))
+	require.False(t, v.Empty())
+
+	v, symbolic, err = ParseSymbolic("v24.1.0")
+	require.NoError(t, err)
+	require.False(t, symbolic)
+	require.False(t, v.IsLatest())
+	require.Equal(t, MustParse("v24.1.0"), v)
+
+	_, _, err = ParseSymbolic("Latest")
+	require.Error(t, err)
+	_, err = Parse("latest")
+	require.Error(t, err)
+
+	// latest sorts after every concrete version
+	versions := []Version{
+		MustParse("v24.1.0"),
+		Latest,
+		MustParse("v99.9.99-customLabel-incompat"),
+		{},
+		MustParse("v24.1.0-rc.1"),
+	}
+	slices.SortFunc(versions, Version.Compare)
+	require.Equal(t, Latest, versions[len(versions)-1])
+	require.Equal(t, 0, Latest.Compare(Latest))
+	for _, w := range versions[:len(versions)-1] {
+		require.Equal(t, 1, Latest.Compare(w), w.String())
+		require.Equal(t, -1, w.Compare(Latest), w.String())
+		require.Less(t, w.SortKey(), Latest.SortKey(), w.String())
+		require.NotEqual(t, w.StableBytes(), Latest.StableBytes(), w.String())
+	}
+	field, _ := Latest.Diff(MustParse("v24.1.0"))
+	require.Equal(t, "latest", field)
+
+	// latest can't be normalized or incremented
+	require.Panics(t, func() { Latest.Normalize() })
+	_, err = Latest.IncPatch()
+	require.Error(t, err)
+}
+
+func TestLatestHasNoFields(t *testing.T) {
+	v := MustParse("v24.1.3")
+
+	require.False(t, Latest.IsPrerelease())
+	_, err := Latest.IncPreRelease()
+	require.Error(t, err)
+	_, err = Latest.IncCloudOnly()
+	require.Error(t, err)
+	_, err = Latest.AdvanceToPhase(Stable)
+	require.Error(t, err)
+	_, err = Latest.RebasePrerelease(MajorVersion{24, 2})
+	require.Error(t, err)
+	_, err = Latest.Next()
+	require.Error(t, err)
+	_, err = Latest.AddPatch(1)
+	require.Error(t, err)
+	_, err = Latest.PatchDistance(v)
+	require.Error(t, err)
+	_, err = PatchRange(v, Latest)
+	require.Error(t, err)
+	_, err = PrereleaseSegments(MustParse("v24.1.0-rc.1"), Latest, nil)
+	require.Error(t, err)
+	_, err = Latest.FormatTemplate("{{.Year}}")
+	require.Error(t, err)
+
+	// methods which return fields, or versions derived from them, panic
+	require.Panics(t, func() { Latest.Major() })
+	require.Panics(t, func() { Latest.Patch() })
+	require.Panics(t, func() { Latest.Phase() })
+	require.Panics(t, func() { Latest.PhaseOrdinal() })
+	require.Panics(t, func() { Latest.PhaseSubOrdinal() })
+	require.Panics(t, func() { Latest.CustomOrdinal() })
+	require.Panics(t, func() { Latest.FinalizationSeries() })
+	require.Panics(t, func() { Latest.Format("v%X.%Y") })
+	require.Panics(t, func() { Latest.WithoutPrerelease() })
+	require.Panics(t, func() { Latest.TargetRelease() })
+	require.Panics(t, func() { Latest.Truncate(Patch) })
+	require.Panics(t, func() { Latest.Truncate(Major) })
+	require.Panics(t, func() { Latest.SamePatch(v) })
+	require.Panics(t, func() { v.CompareSeries(Latest) })
+	require.Panics(t, func() { Latest.HasReachedSeries(v.Major()) })
+
+	require.Panics(t, func() { MajorVersion{}.Contains(Latest) })
+
+	_, err = Latest.CompareSafe(v)
+	require.ErrorContains(t, err, "latest")
+	_, err = v.CompareSafe(Latest)
+	require.ErrorContains(t, err, "latest")
+}
+
+func TestParseTrimmed(t *testing.T) {
+	for _, str := range []string{
+		"v24.1.0-rc.1",
+		"  v24.1.0-rc.1  ",
+		"\tv24.1.0-rc.1",
+		"v24.1.0-rc.1\n",
+		"\r\n\t v24.1.0-rc.1 \t\r\n",
+	} {
+		v, err := ParseTrimmed(str)
+		require.NoError(t, err, "%q", str)
+		require.Equal(t, MustParse("v24.1.0-rc.1"), v)
+		require.Equal(t, "v24.1.0-rc.1", v.String())
+	}
+
+	for _, str := range []string{"", " \t\n", "v24.1 .0", " 24.1.0 "} {
+		_, err := ParseTrimmed(str)
+		require.Error(t, err, "%q", str)
+	}
+
+	// Parse still rejects padded input
+	for _, str := range []string{" v24.1.0", "v24.1.0\n", "\tv24.1.0"} {
+		_, err := Parse(str)
+		require.Error(t, err, "%q", str)
+	}
+}
+
+func TestParseOrDefault(t *testing.T) {
+	def := MustParse("v24.1.0-cloudonly-rc1")
+	require.Equal(t, MustParse("v23.2.4"), ParseOrDefault("v23.2.4", def))
+	for _, str := range []string{"", "24.1.0", "v24.1", " v23.2.4", "latest"} {
+		got := ParseOrDefault(str, def)
+		require.Equal(t, def, got, str)
+		require.True(t, got.EqualsRaw(def), str)
+	}
+	require.Equal(t, Version{}, ParseOrDefault("bogus", Version{}))
+}
+
+func TestParseOrZero(t *testing.T) {
+	for _, str := range []string{"", " ", "\t\n"} {
+		v, err := ParseOrZero(str)
+		require.NoError(t, err)
+		require.True(t, v.Empty())
+	}
+
+	v, err := ParseOrZero("v24.1.0-rc.1")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.0-rc.1"), v)
+
+	for _, str := range []string{"v24.1", " v24.1.0"} {
+		_, err = ParseOrZero(str)
+		require.ErrorContains(t, err, "invalid version string")
+	}
+}
+
+// benchmarkVersions returns n versions of assorted shapes, many of which share
+// a release series or are otherwise close to one another.
+func benchmarkVersions(n int) []Version {
+	rng := rand.New(rand.NewSource(1))
+	suffixes := []string{"", "", "", "-alpha.%d", "-beta.%d", "-rc.%d", "-cloudonly.%d", "-rc.1-cloudonly.%d", "-%d-gabcdef1", "-build.%d", "-fips"}
+	parsed := map[string]Version{}
+	vs := make([]Version, n)
+	for i := range vs {
+		suffix := suffixes[rng.Intn(len(suffixes))]
+		if strings.Contains(suffix, "%d") {
+			suffix = fmt.Sprintf(suffix, rng.Intn(4)+1)
+		}
+		str := fmt.Sprintf("v%d.%d.%d%s", 22+rng.Intn(4), 1+rng.Intn(2), rng.Intn(4), suffix)
+		if _, ok := parsed[str]; !ok {
+			parsed[str] = MustParse(str)
+		}
+		vs[i] = parsed[str]
+	}
+	return vs
+}
+
+func BenchmarkCompare(b *testing.B) {
+	vs := benchmarkVersions(1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = vs[i%len(vs)].Compare(vs[(i+1)%len(vs)])
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	vs := benchmarkVersions(100_000)
+	sorted := make([]Version, len(vs))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(sorted, vs)
+		slices.SortFunc(sorted, Version.Compare)
+	}
+}
+
+// TestCompareMatchesDiff ensures that Compare's inlined field comparisons stay
+// in sync with compareFields.
+func TestCompareMatchesDiff(t *testing.T) {
+	vs := benchmarkVersions(500)
+	vs = append(vs, MustParse("v24.1.0-incompat"), MustParse("v24.1.0-build.01"), MustParse("v24.1.0-build.1"), Version{})
+	for _, v := range vs {
+		for _, w := range vs {
+			_, want := v.Diff(w)
+			require.Equalf(t, want, v.Compare(w), "%s vs %s", v, w)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	strs := []string{
+		"v24.1.0",
+		"v24.1.0-rc.1",
+		"v22.2.10-1-g7b8322d67c-fips",
+		"v24.3.0-alpha.1-cloudonly.1",
+		"v23.1.12-cloudonly-rc1",
+		"v23.1.0-swenson-mr-4",
+		"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(strs[i%len(strs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAdhocVersion(t *testing.T) {
+	v, err := AdhocVersion(MustParse("v24.1.0"), 12, "abcdef1")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.0-12-gabcdef1"), v)
+	commits, ok := v.CommitsSinceTag()
+	require.True(t, ok)
+	require.Equal(t, 12, commits)
+
+	v, err = AdhocVersion(MustParse("v22.2.10-fips"), 1, "7b8322d67c")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v22.2.10-1-g7b8322d67c-fips"), v)
+
+	for _, tc := range []struct {
+		base    string
+		commits int
+		sha     string
+	}{
+		{"v24.1.0-rc.1", 12, "abcdef1"},
+		{"v24.1.0-cloudonly.1", 12, "abcdef1"},
+		{"v24.1.0-12-gabcdef1", 12, "abcdef1"},
+		{"v24.1.0-foo", 12, "abcdef1"},
+		{"v24.1.0", 0, "abcdef1"},
+		{"v24.1.0", -1, "abcdef1"},
+		{"v24.1.0", 12, "ABCDEF1"},
+		{"v24.1.0", 12, "abc"},
+		{"v24.1.0", 12, "abcdefg"},
+		{"v24.1.0", 12, "abcdef1-foo"},
+		{"v24.1.0", 12, ""},
+	} {
+		_, err := AdhocVersion(MustParse(tc.base), tc.commits, tc.sha)
+		require.Errorf(t, err, "expected error for %s, %d, %q", tc.base, tc.commits, tc.sha)
+	}
+	_, err = AdhocVersion(Version{}, 12, "abcdef1")
+	require.Error(t, err)
+}
+
+func TestSortKey(t *testing.T) {
+	require.Equal(t, "022401101030110100100!0", MustParse("v24.1.0-rc.1").SortKey())
+
+	ordered := mustParseAll(
+		"v1.2.3",
+		"v9.1.0",
+		"v24.1.0-alpha.1",
+		"v24.1.0-alpha.10",
+		"v24.1.0-beta.2",
+		"v24.1.0-rc.1",
+		"v24.1.0-rc.1-cloudonly.2",
+		"v24.1.0-rc.1-12-gabcdef1",
+		"v24.1.0-cloudonly.1",
+		"v24.1.0",
+		"v24.1.0-fips",
+		"v24.1.0-incompat",
+		"v24.1.0-fips-incompat",
+		"v24.1.0-3-gabcdef1",
+		"v24.1.0-build",
+		"v24.1.0-build.1",
+		"v24.1.0-build.01",
+		"v24.1.0-build.2",
+		"v24.1.0-build.10",
+		"v24.1.0-build.x",
+		"v24.1.0-build.x.y",
+		"v24.1.9",
+		"v24.1.10",
+		"v24.10.0",
+		"v100.1.0",
+	)
+	for i := range ordered {
+		for j := range ordered {
+			a, b := ordered[i], ordered[j]
+			require.Equalf(t, a.Compare(b), strings.Compare(a.SortKey(), b.SortKey()),
+				"%s (%s) vs %s (%s)", a, a.SortKey(), b, b.SortKey())
+		}
+	}
+
+	// equal versions have equal keys
+	require.Equal(t, MustParse("v24.1.0-cloudonly.1").SortKey(), MustParse("v24.1.0-cloudonly-rc1").SortKey())
+}
+
+func FuzzSortKey(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"v24.1.0", "v24.1.0-rc.1"},
+		{"v24.1.9", "v24.1.10"},
+		{"v24.1.0-build.01", "v24.1.0-build.1"},
+		{"v24.1.0-a.0", "v24.1.0-a.00"},
+		{"v24.1.0-a..b", "v24.1.0-a.b"},
+		{"v24.1.0-a-b", "v24.1.0-a"},
+		{"v23.2.0-alpha.00000000-4376-g7450647f213", "v23.2.0-alpha.0"},
+		{"v24.1.0-incompat", "v24.1.0-1-gabcdef1"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		va, err := Parse(a)
+		if err != nil {
+			return
+		}
+		vb, err := Parse(b)
+		if err != nil {
+			return
+		}
+		if got, want := strings.Compare(va.SortKey(), vb.SortKey()), va.Compare(vb); got != want {
+			t.Fatalf("%s vs %s: SortKey comparison %d, Compare %d (%q vs %q)", a, b, got, want, va.SortKey(), vb.SortKey())
+		}
+	})
+}
+
+func TestParseOutOfRange(t *testing.T) {
+	for _, str := range []string{
+		"v99999999999999999999.1.0",
+		"v24.99999999999999999999.0",
+		"v24.1.99999999999999999999",
+		"v24.1.0-rc.99999999999999999999",
+		"v24.1.0-rc.1-cloudonly.99999999999999999999",
+		"v24.1.0-99999999999999999999-gabcdef1",
+	} {
+		_, err := Parse(str)
+		require.ErrorContains(t, err, "is out of range", str)
+	}
+
+	// the largest values parse, but can't be incremented
+	v := MustParse("v24.1.9223372036854775807")
+	_, err := v.IncPatch()
+	require.Error(t, err)
+	v = MustParse("v24.1.0-rc.9223372036854775807")
+	_, err = v.IncPreRelease()
+	require.Error(t, err)
+	v = MustParse("v24.1.0-rc.1-cloudonly.9223372036854775807")
+	_, err = v.IncCloudOnly()
+	require.Error(t, err)
+}
+
+func FuzzParseRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		"v24.1.0",
+		"v24.1.0-rc.1",
+		"v24.1.0-fips",
+		"v23.1.0-alpha.1-1643-gdf8e73734e-fips",
+		"v24.3.0-alpha.1-cloudonly.1",
+		"v23.2.0-beta.1-cloudonly-rc1",
+		"v23.1.12-cloudonly-rc2",
+		"v23.1.11-cloudonly2",
+		"v23.2.0-cloudonly",
+		"v23.1.0-swenson-mr-4",
+		"v23.2.0-alpha.00000000",
+		"v24.1.0-rc.1-incompat+gpu",
+		"v24.1.9223372036854775807",
+		"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, str string) {
+		v, err := Parse(str)
+		if err != nil {
+			return
+		}
+		require.Equal(t, str, v.String())
+
+		// re-formatting and re-parsing gives an equal version
+		for _, formatted := range []string{v.String(), v.Normalize().String()} {
+			reparsed, err := Parse(formatted)
+			require.NoError(t, err, formatted)
+			require.True(t, v.Equals(reparsed), "%s reparsed from %s", str, formatted)
+		}
+		_ = v.Format("%X.%Y.%Z %p %P %o %s %n")
+
+		// incremented versions, when they can be produced, round-trip too
+		for _, inc := range []func() (Version, error){v.IncPatch, v.IncPreRelease, v.IncCloudOnly} {
+			next, err := inc()
+			if err != nil {
+				continue
+			}
+			reparsed, err := Parse(next.String())
+			require.NoError(t, err, next.String())
+			require.Equal(t, next, reparsed)
+			require.Equal(t, 1, next.Compare(v), "%s -> %s", str, next.String())
+		}
+	})
+}
+
+func TestSamePatch(t *testing.T) {
+	v := MustParse("v24.1.0")
+	for _, str := range []string{"v24.1.0", "v24.1.0-rc.1", "v24.1.0-alpha.1-cloudonly.2", "v24.1.0-12-gabcdef1", "v24.1.0-fips", "v24.1.0-foo"} {
+		require.True(t, v.SamePatch(MustParse(str)), str)
+		require.True(t, MustParse(str).SamePatch(v), str)
+	}
+	for _, str := range []string{"v24.1.1", "v24.1.1-rc.1", "v24.2.0", "v23.1.0"} {
+		require.False(t, v.SamePatch(MustParse(str)), str)
+	}
+}
+
+func TestPhases(t *testing.T) {
+	require.Equal(t, []Phase{Alpha, Beta, RC, CloudOnly}, Phases(false))
+	require.Equal(t, []Phase{Alpha, Beta, RC, CloudOnly, Stable, Adhoc}, Phases(true))
+
+	// phases are listed in sort order
+	phases := Phases(true)
+	require.True(t, slices.IsSorted(phases))
+
+	var names []string
+	for _, p := range phases {
+		names = append(names, p.String())
+	}
+	require.Equal(t, []string{"alpha", "beta", "rc", "cloudonly", "stable", "adhoc"}, names)
+	require.Equal(t, "Phase(0)", Phase(0).String())
+	require.Equal(t, "rc", fmt.Sprint(MustParse("v24.1.0-rc.1").Phase()))
+}
+
+func TestPatchDistance(t *testing.T) {
+	testCases := []struct {
+		v, w string
+		want int
+	}{
+		{"v24.1.2", "v24.1.5", 3},
+		{"v24.1.5", "v24.1.2", -3},
+		{"v24.1.2", "v24.1.2", 0},
+		{"v24.1.0", "v24.1.12-fips", 12},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v+" to "+tc.w, func(t *testing.T) {
+			d, err := MustParse(tc.v).PatchDistance(MustParse(tc.w))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, d)
+		})
+	}
+
+	_, err := MustParse("v24.1.2").PatchDistance(MustParse("v24.2.0"))
+	require.ErrorContains(t, err, "different release series")
+	_, err = MustParse("v24.1.0-rc.1").PatchDistance(MustParse("v24.1.2"))
+	require.ErrorContains(t, err, "both must be stable versions")
+	_, err = MustParse("v24.1.2").PatchDistance(MustParse("v24.1.0-cloudonly.1"))
+	require.ErrorContains(t, err, "both must be stable versions")
+}
+
+func TestIsImmediatePatchSuccessor(t *testing.T) {
+	testCases := []struct {
+		v, w string
+		want bool
+	}{
+		{"v24.1.2", "v24.1.3", true},
+		{"v24.1.0", "v24.1.1-fips", true},
+		{"v24.1.2", "v24.1.4", false},
+		{"v24.1.3", "v24.1.2", false},
+		{"v24.1.2", "v24.1.2", false},
+		{"v24.1.4", "v24.2.0", false},
+		{"v24.1.0-rc.1", "v24.1.1", false},
+		{"v24.1.0", "v24.1.1-rc.1", false},
+		{"v24.1.0-cloudonly.1", "v24.1.1", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v+" to "+tc.w, func(t *testing.T) {
+			require.Equal(t, tc.want, MustParse(tc.v).IsImmediatePatchSuccessor(MustParse(tc.w)))
+		})
+	}
+}
+
+func TestRegisterPhaseAlias(t *testing.T) {
+	// without an alias, "preview" is an adhoc label
+	v := MustParse("v24.1.0-preview.1")
+	require.Equal(t, Adhoc, v.Phase())
+	require.Equal(t, 1, v.Compare(MustParse("v24.1.0")))
+
+	RegisterPhaseAlias("preview", RC)
+	RegisterPhaseAlias("preview", RC) // re-registering is a no-op
+
+	for _, tc := range []struct {
+		aliased string
+		want    string
+	}{
+		{"v24.1.0-preview.1", "v24.1.0-rc.1"},
+		{"v24.1.0-preview.2-12-gabcdef1", "v24.1.0-rc.2-12-gabcdef1"},
+		{"v24.1.0-preview.1-cloudonly.2", "v24.1.0-rc.1-cloudonly.2"},
+		{"v24.1.0-preview.1-fips", "v24.1.0-rc.1-fips"},
+	} {
+		v := MustParse(tc.aliased)
+		require.Equal(t, tc.aliased, v.String())
+		require.True(t, v.Equals(MustParse(tc.want)), tc.aliased)
+		require.True(t, v.IsReleaseCandidate(), tc.aliased)
+	}
+	require.Equal(t, "v24.1.0-rc.1", MustParse("v24.1.0-preview.1").Normalize().String())
+
+	// other words are still adhoc labels
+	require.Equal(t, Adhoc, MustParse("v24.1.0-previews.1").Phase())
+	require.Equal(t, Adhoc, MustParse("v24.1.0-foo.1-12-gabcdef1").Phase())
+
+	require.Panics(t, func() { RegisterPhaseAlias("Pre", RC) })
+	require.Panics(t, func() { RegisterPhaseAlias("pre-release", RC) })
+	require.Panics(t, func() { RegisterPhaseAlias("", RC) })
+	require.Panics(t, func() { RegisterPhaseAlias("pre", Stable) })
+	require.Panics(t, func() { RegisterPhaseAlias("pre", Adhoc) })
+	require.Panics(t, func() { RegisterPhaseAlias("alpha", Beta) })
+	require.Panics(t, func() { RegisterPhaseAlias("preview", Beta) })
+}
+
+func TestNext(t *testing.T) {
+	for _, tc := range []struct {
+		v    string
+		want string
+	}{
+		{"v24.1.3", "v24.1.4"},
+		{"v24.1.3-fips", "v24.1.4-fips"},
+		{"v24.1.0-alpha.1", "v24.1.0-alpha.2"},
+		{"v24.1.0-beta.3", "v24.1.0-beta.4"},
+		{"v24.1.0-rc.2", "v24.1.0-rc.3"},
+	} {
+		next, err := MustParse(tc.v).Next()
+		require.NoError(t, err, tc.v)
+		require.Equal(t, MustParse(tc.want), next)
+	}
+
+	// the end of the train: rc.N is followed by the stable release, which
+	// must be requested explicitly
+	final := MustParse("v24.1.0-rc.4")
+	ga, err := final.AdvanceToPhase(Stable)
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.0"), ga)
+	next, err := ga.Next()
+	require.NoError(t, err)
+	require.Equal(t, MustParse("v24.1.1"), next)
+
+	for _, str := range []string{
+		"v24.1.0-12-gabcdef1",
+		"v24.1.0-rc.1-12-gabcdef1",
+		"v23.1.0-swenson-mr-4",
+		"v24.1.0-cloudonly.1",
+		"v24.3.0-alpha.1-cloudonly.1",
+		"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build",
+	} {
+		_, err := MustParse(str).Next()
+		require.Error(t, err, str)
+	}
+	_, err = Version{}.Next()
+	require.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	for _, tc := range []struct {
+		v            string
+		major, patch string
+	}{
+		{"v24.1.3-rc.1", "v24.1.0", "v24.1.3"},
+		{"v24.1.3", "v24.1.0", "v24.1.3"},
+		{"v24.1.0", "v24.1.0", "v24.1.0"},
+		{"v24.1.3-12-gabcdef1-fips", "v24.1.0", "v24.1.3"},
+		{"v24.3.0-alpha.1-cloudonly.1", "v24.3.0", "v24.3.0"},
+		{"v23.1.2-swenson-mr-4-incompat+gpu", "v23.1.0", "v23.1.2"},
+		{"sha256:6bbf843734d11db9cc5eb8ea77f6974032e17ad216c91ccecfaf52a4890eaa11:latest-v22.2-build", "v22.2.0", "v22.2.0"},
+	} {
+		v := MustParse(tc.v)
+		require.Equal(t, MustParse(tc.major), v.Truncate(Major), tc.v)
+		require.Equal(t, MustParse(tc.patch), v.Truncate(Patch), tc.v)
+	}
+	require.Equal(t, Version{}, Version{}.Truncate(Major))
+	require.Panics(t, func() { MustParse("v24.1.3").Truncate(Granularity(0)) })
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import "slices"
+
+// CommonSeries returns the lowest release series among vs, ie the series that
+// every version is at least on. Empty versions are ignored; if there are no
+// non-empty versions, CommonSeries returns false.
+func CommonSeries(vs []Version) (MajorVersion, bool) {
+	var lowest MajorVersion
+	found := false
+	for _, v := range vs {
+		if v.Empty() {
+			continue
+		}
+		if !found || v.Major().LessThan(lowest) {
+			lowest = v.Major()
+			found = true
+		}
+	}
+	return lowest, found
+}
+
+// AllAtLeast returns true if every version in vs is at least floor. It stops
+// at the first version that isn't, and returns true (vacuously) if vs is
+// empty.
+func AllAtLeast(vs []Version, floor Version) bool {
+	for _, v := range vs {
+		if !v.AtLeast(floor) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllAtMost returns true if no version in vs is greater than ceiling. It stops
+// at the first version that is, and returns true (vacuously) if vs is empty.
+func AllAtMost(vs []Version, ceiling Version) bool {
+	for _, v := range vs {
+		if v.Compare(ceiling) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Series returns the distinct release series of vs, in ascending order.
+// Empty versions are ignored.
+func Series(vs []Version) []MajorVersion {
+	var series []MajorVersion
+	for _, v := range vs {
+		if !v.Empty() {
+			series = append(series, v.Major())
+		}
+	}
+	slices.SortFunc(series, MajorVersion.Compare)
+	return slices.CompactFunc(series, MajorVersion.Equals)
+}
+
+// BinarySearch searches for target in vs, which must be sorted in ascending
+// order (see [Version.Compare]). Like [slices.BinarySearchFunc], it returns the
+// position where target is found, or where it would be inserted, and whether
+// it was found. Versions are compared with Compare, so prerelease ordering is
+// respected, and versions which are [Version.Equals] to target but were parsed
+// from differently-spelled strings are matches.
+func BinarySearch(vs []Version, target Version) (int, bool) {
+	return slices.BinarySearchFunc(vs, target, Version.Compare)
+}
+
+// ParseAll parses each of strs, returning the versions and errors in slices
+// parallel to strs: for each string that fails to parse, the version is the
+// zero Version, and the error is the one returned by [Parse]. If every string
+// parses, the returned errors slice is nil; otherwise, it contains a nil error
+// for each string that parsed.
+func ParseAll(strs []string) ([]Version, []error) {
+	vs := make([]Version, len(strs))
+	var errs []error
+	for i, str := range strs {
+		v, err := Parse(str)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(strs))
+			}
+			errs[i] = err
+			continue
+		}
+		vs[i] = v
+	}
+	return vs, errs
+}
+
+// Dedup returns the distinct versions in vs, in the order they first appear.
+// Versions are distinct if they aren't [Version.Equals], so of several spellings
+// of the same version (eg "v24.1.0-cloudonly.1" and "v24.1.0-cloudonly-rc1"),
+// only the first is kept. Note that a map[Version] doesn't dedupe these, since
+// its keys differ by the original version string. vs is not modified.
+func Dedup(vs []Version) []Version {
+	seen := make(map[string]struct{}, len(vs))
+	var distinct []Version
+	for _, v := range vs {
+		key := string(v.StableBytes())
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		distinct = append(distinct, v)
+	}
+	return distinct
+}
+
+// FilterAtLeastSeries returns a new slice of the versions in vs that have
+// reached release series m (see [Version.HasReachedSeries]), in their original
+// order.
+func FilterAtLeastSeries(vs []Version, m MajorVersion) []Version {
+	var filtered []Version
+	for _, v := range vs {
+		if v.HasReachedSeries(m) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mustParseAll parses each of strs, panicking on any error.
+func mustParseAll(strs ...string) []Version {
+	vs := make([]Version, len(strs))
+	for i, str := range strs {
+		vs[i] = MustParse(str)
+	}
+	return vs
+}
+
+func TestCommonSeries(t *testing.T) {
+	series, ok := CommonSeries(mustParseAll("v24.1.3", "v23.2.0-rc.1", "v24.2.0", "v23.2.8"))
+	require.True(t, ok)
+	require.Equal(t, MustParseMajorVersion("v23.2"), series)
+
+	series, ok = CommonSeries(mustParseAll("v24.1.3"))
+	require.True(t, ok)
+	require.Equal(t, MustParseMajorVersion("v24.1"), series)
+
+	series, ok = CommonSeries([]Version{{}, MustParse("v24.1.3")})
+	require.True(t, ok)
+	require.Equal(t, MustParseMajorVersion("v24.1"), series)
+
+	_, ok = CommonSeries(nil)
+	require.False(t, ok)
+
+	_, ok = CommonSeries([]Version{{}})
+	require.False(t, ok)
+}
+
+func TestAllAtLeastAtMost(t *testing.T) {
+	fleet := mustParseAll("v24.1.3", "v24.1.0", "v24.2.0-rc.1")
+
+	// all pass
+	require.True(t, AllAtLeast(fleet, MustParse("v24.1.0")))
+	require.True(t, AllAtMost(fleet, MustParse("v24.2.0-rc.1")))
+
+	// one fails
+	require.False(t, AllAtLeast(fleet, MustParse("v24.1.1")))
+	require.False(t, AllAtMost(fleet, MustParse("v24.2.0-beta.1")))
+
+	// empty
+	require.True(t, AllAtLeast(nil, MustParse("v24.1.0")))
+	require.True(t, AllAtMost(nil, MustParse("v24.1.0")))
+}
+
+func TestSeries(t *testing.T) {
+	series := Series(mustParseAll("v24.1.3", "v23.2.0-rc.1", "v24.2.0", "v24.1.0", "v23.2.8", "v24.1.3-12-gabcdef1"))
+	require.Equal(t, []MajorVersion{
+		MustParseMajorVersion("v23.2"),
+		MustParseMajorVersion("v24.1"),
+		MustParseMajorVersion("v24.2"),
+	}, series)
+
+	require.Equal(t, []MajorVersion{MustParseMajorVersion("v24.1")}, Series([]Version{{}, MustParse("v24.1.3"), {}}))
+	require.Empty(t, Series(nil))
+	require.Empty(t, Series([]Version{{}}))
+}
+
+func TestBinarySearch(t *testing.T) {
+	vs := mustParseAll("v24.1.0-beta.1", "v24.1.0-rc.1", "v24.1.0-cloudonly.1", "v24.1.0", "v24.1.1", "v24.2.0")
+	require.True(t, slices.IsSortedFunc(vs, Version.Compare))
+
+	for _, tc := range []struct {
+		target string
+		index  int
+		found  bool
+	}{
+		{"v24.1.0-rc.1", 1, true},
+		{"v24.1.0-cloudonly1", 2, true}, // equal to, but spelled differently than, v24.1.0-cloudonly.1
+		{"v24.1.0", 3, true},
+		{"v24.2.0", 5, true},
+		{"v24.1.0-alpha.1", 0, false},
+		{"v24.1.0-rc.2", 2, false},
+		{"v24.1.0-12-gabcdef1", 4, false},
+		{"v24.3.0", 6, false},
+	} {
+		index, found := BinarySearch(vs, MustParse(tc.target))
+		require.Equal(t, tc.index, index, tc.target)
+		require.Equal(t, tc.found, found, tc.target)
+	}
+
+	index, found := BinarySearch(nil, MustParse("v24.1.0"))
+	require.Equal(t, 0, index)
+	require.False(t, found)
+}
+
+func TestParseAll(t *testing.T) {
+	vs, errs := ParseAll([]string{"v24.1.0", "v24.1.0-rc.1"})
+	require.Nil(t, errs)
+	require.Equal(t, mustParseAll("v24.1.0", "v24.1.0-rc.1"), vs)
+
+	vs, errs = ParseAll([]string{"v24.1.0", "v24.1", "v24.1.0-rc.1", "garbage"})
+	require.Equal(t, []Version{MustParse("v24.1.0"), {}, MustParse("v24.1.0-rc.1"), {}}, vs)
+	require.Len(t, errs, 4)
+	require.NoError(t, errs[0])
+	require.ErrorContains(t, errs[1], "invalid version string 'v24.1'")
+	require.NoError(t, errs[2])
+	require.ErrorContains(t, errs[3], "invalid version string 'garbage'")
+
+	vs, errs = ParseAll(nil)
+	require.Empty(t, vs)
+	require.Nil(t, errs)
+}
+
+func TestDedup(t *testing.T) {
+	vs := []Version{
+		MustParse("v24.1.0-cloudonly-rc1"),
+		MustParse("v23.2.4"),
+		MustParse("v24.1.0-cloudonly.1"),
+		MustParse("v23.2.4-fips"),
+		MustParse("v24.1.0-cloudonly1"),
+		MustParse("v24.1.0"),
+		MustParse("v23.2.4"),
+	}
+	require.Equal(t, []Version{
+		MustParse("v24.1.0-cloudonly-rc1"),
+		MustParse("v23.2.4"),
+		MustParse("v23.2.4-fips"),
+		MustParse("v24.1.0"),
+	}, Dedup(vs))
+	require.Equal(t, MustParse("v24.1.0-cloudonly.1"), vs[2])
+
+	require.Empty(t, Dedup(nil))
+}
+
+func TestFilterAtLeastSeries(t *testing.T) {
+	m := MustParseMajorVersion("v24.1")
+	require.True(t, MustParse("v24.1.0-alpha.1").HasReachedSeries(m))
+	require.True(t, MustParse("v24.2.3").HasReachedSeries(m))
+	require.False(t, MustParse("v23.2.28").HasReachedSeries(m))
+	require.False(t, Version{}.HasReachedSeries(m))
+
+	vs := []Version{
+		MustParse("v24.2.0"),
+		MustParse("v23.2.28"),
+		MustParse("v24.1.0-rc.1"),
+		MustParse("v23.1.0"),
+		MustParse("v24.1.3"),
+	}
+	require.Equal(t, []Version{
+		MustParse("v24.2.0"),
+		MustParse("v24.1.0-rc.1"),
+		MustParse("v24.1.3"),
+	}, FilterAtLeastSeries(vs, m))
+	require.Len(t, vs, 5)
+	require.Empty(t, FilterAtLeastSeries(vs, MustParseMajorVersion("v25.1")))
+}
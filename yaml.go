@@ -0,0 +1,49 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+// The methods in this file satisfy the (function-based) marshaling interfaces
+// understood by both gopkg.in/yaml.v2 and gopkg.in/yaml.v3, so this package
+// doesn't need to depend on either.
+
+// MarshalYAML implements [gopkg.in/yaml.v3.Marshaler]. Unlike MarshalJSON, a
+// Version is written as a plain scalar string; the zero Version is written as
+// an empty string.
+func (v Version) MarshalYAML() (interface{}, error) {
+	if err := v.checkMarshalable(); err != nil {
+		return nil, err
+	}
+	return v.raw, nil
+}
+
+// UnmarshalYAML implements the obsolete yaml.v3 Unmarshaler interface (which is
+// the yaml.v2 Unmarshaler interface). An empty scalar unmarshals to the zero
+// Version.
+func (v *Version) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*v = Version{}
+		return nil
+	}
+	parsed, err := Parse(str)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
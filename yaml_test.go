@@ -0,0 +1,85 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestVersionYAMLSerialization(t *testing.T) {
+	type manifest struct {
+		Version Version `yaml:"version"`
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		m := manifest{Version: MustParse("v20.1.2-alpha.3-cloudonly.4")}
+
+		blob, err := yaml.Marshal(m)
+		require.NoError(t, err)
+		require.Equal(t, "version: v20.1.2-alpha.3-cloudonly.4\n", string(blob))
+
+		var parsed manifest
+		err = yaml.Unmarshal(blob, &parsed)
+		require.NoError(t, err)
+		require.Equal(t, m, parsed)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		blob, err := yaml.Marshal(manifest{})
+		require.NoError(t, err)
+
+		var parsed manifest
+		err = yaml.Unmarshal(blob, &parsed)
+		require.NoError(t, err)
+		require.True(t, parsed.Version.Empty())
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		_, err := yaml.Marshal(manifest{Version: Latest})
+		require.ErrorContains(t, err, "latest")
+
+		var parsed manifest
+		err = yaml.Unmarshal([]byte("version: latest\n"), &parsed)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var parsed manifest
+		err := yaml.Unmarshal([]byte("version: 24.1.0\n"), &parsed)
+		require.ErrorContains(t, err, "invalid version string")
+
+		err = yaml.Unmarshal([]byte("version: [v24.1.0]\n"), &parsed)
+		require.Error(t, err)
+	})
+}
+
+func TestPhaseYAML(t *testing.T) {
+	type config struct {
+		MinPhase Phase `yaml:"minPhase"`
+	}
+
+	blob, err := yaml.Marshal(config{MinPhase: CloudOnly})
+	require.NoError(t, err)
+	require.Equal(t, "minPhase: cloudonly\n", string(blob))
+
+	var parsed config
+	require.NoError(t, yaml.Unmarshal([]byte("minPhase: rc\n"), &parsed))
+	require.Equal(t, RC, parsed.MinPhase)
+
+	require.ErrorContains(t, yaml.Unmarshal([]byte("minPhase: gamma\n"), &parsed), "unknown phase 'gamma'")
+}